@@ -0,0 +1,123 @@
+// Command componentgen generates the component.go and logging.go files of a
+// component package: its [Component] type, constructed with the common
+// configuration and started through the start function of the package, and
+// its component logger. It is meant to be run through go generate from the
+// package directory, for example:
+//
+//	//go:generate go run ../cmd/componentgen -name health
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// header marks the generated files.
+const header = "// Code generated by go generate; DO NOT EDIT.\n\n"
+
+// files holds the templates of the generated files, by file name.
+var files = map[string]*template.Template{
+	"component.go": template.Must(template.New("component.go").Parse(header + `package {{.Package}}
+
+import (
+	"context"
+	"sync"
+
+	"github.com/stellarentropy/gravity-assist-common/config/common"
+)
+
+var componentName = "gravity-assist.{{.Name}}"
+
+type Component struct {
+	config *common_config.CommonConfig
+}
+
+func NewComponent(c *common_config.CommonConfig) *Component {
+	return &Component{config: c}
+}
+
+func (d *Component) Start(ctx context.Context, wg *sync.WaitGroup) {
+	start(ctx, wg, d.config)
+}
+
+func (d *Component) Name() string {
+	return "{{.Name}}"
+}
+`)),
+	"logging.go": template.Must(template.New("logging.go").Parse(header + `package {{.Package}}
+
+import (
+	"github.com/stellarentropy/gravity-assist-common/logging"
+)
+
+var logger logging.Logger
+
+func init() {
+	logger = logging.Logger{Logger: logging.GetLogger().With().Str("component", componentName).Logger()}
+}
+`)),
+}
+
+// render returns the content of the generated files of the component name in
+// package pkg, by file name.
+func render(pkg string, name string) (map[string][]byte, error) {
+	out := make(map[string][]byte, len(files))
+
+	for file, tmpl := range files {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, struct{ Package, Name string }{pkg, name}); err != nil {
+			return nil, err
+		}
+
+		b, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, err
+		}
+
+		out[file] = b
+	}
+
+	return out, nil
+}
+
+// generate writes the generated files of the component name in package pkg
+// to dir.
+func generate(dir string, pkg string, name string) error {
+	out, err := render(pkg, name)
+	if err != nil {
+		return err
+	}
+
+	for file, b := range out {
+		if err := os.WriteFile(filepath.Join(dir, file), b, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func main() {
+	name := flag.String("name", "", "name of the component")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "name of the package, set by go generate")
+	dir := flag.String("dir", ".", "directory of the package")
+	flag.Parse()
+
+	var err error
+
+	if *name == "" || *pkg == "" {
+		err = fmt.Errorf("both -name and -package are required")
+	} else {
+		err = generate(*dir, *pkg, *name)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "componentgen:", err)
+		os.Exit(1)
+	}
+}
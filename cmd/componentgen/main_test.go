@@ -0,0 +1,28 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestComponentsUpToDate verifies that the committed files of every component
+// package match the templates. Run go generate in the package to update them.
+func TestComponentsUpToDate(t *testing.T) {
+	for _, c := range []struct{ dir, pkg, name string }{
+		{"../../debug", "debug", "debug"},
+		{"../../health", "health", "health"},
+		{"../../metrics/tracer", "tracer", "tracer"},
+	} {
+		out, err := render(c.pkg, c.name)
+		assert.NoError(t, err)
+
+		for file, expected := range out {
+			b, err := os.ReadFile(filepath.Join(c.dir, file))
+			assert.NoError(t, err)
+			assert.Equal(t, string(expected), string(b), "%s/%s is stale, run go generate in the package", c.dir, file)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Command configdoc generates the reference documentation of the environment
+// variables registered with config.Register, as Markdown or JSON. It is meant
+// to be run through go generate, for example:
+//
+//	//go:generate go run ../../cmd/configdoc -format markdown -out ../../docs/config.md
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/stellarentropy/gravity-assist-common/config"
+
+	// Register the variables of the common and object storage configurations
+	_ "github.com/stellarentropy/gravity-assist-common/config/common"
+	_ "github.com/stellarentropy/gravity-assist-common/objectstorage"
+)
+
+func main() {
+	format := flag.String("format", "markdown", "output format, markdown or json")
+	out := flag.String("out", "", "output file, the standard output if empty")
+	flag.Parse()
+
+	var b []byte
+	var err error
+
+	defs := config.Registered()
+
+	switch *format {
+	case "markdown":
+		b = config.RenderMarkdown(defs)
+	case "json":
+		b, err = config.RenderJSON(defs)
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+
+	if err == nil {
+		if *out == "" {
+			_, err = os.Stdout.Write(b)
+		} else {
+			err = os.WriteFile(*out, b, 0644)
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "configdoc:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stellarentropy/gravity-assist-common/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDocsUpToDate verifies that the committed configuration reference matches
+// the definitions registered by the packages imported by configdoc. Run go
+// generate ./config/common/ to update it.
+func TestDocsUpToDate(t *testing.T) {
+	defs := config.Registered()
+
+	md, err := os.ReadFile("../../docs/config.md")
+	assert.NoError(t, err)
+	assert.Equal(t, string(config.RenderMarkdown(defs)), string(md), "docs/config.md is stale, run go generate ./config/common/")
+
+	expected, err := config.RenderJSON(defs)
+	assert.NoError(t, err)
+
+	js, err := os.ReadFile("../../docs/config.json")
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(js), "docs/config.json is stale, run go generate ./config/common/")
+}
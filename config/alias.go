@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+)
+
+// Alias is a deprecated key of an environment variable, still read when the
+// variable is not set under its current key, see [Env.WithAliases].
+type Alias struct {
+	// Key is the deprecated key.
+	Key string
+
+	// Since optionally tells when the key was deprecated, such as a version
+	// or a date, so that operators know how long it has been around.
+	Since string
+}
+
+// String renders the alias as KEY, or as KEY@since when [Alias.Since] is set,
+// the format of the [TagAliases] tag.
+func (a Alias) String() string {
+	if a.Since == "" {
+		return a.Key
+	}
+
+	return a.Key + "@" + a.Since
+}
+
+// ParseAlias parses an alias written as KEY or KEY@since, the format of the
+// [TagAliases] tag.
+func ParseAlias(s string) Alias {
+	key, since, _ := strings.Cut(strings.TrimSpace(s), "@")
+
+	return Alias{Key: key, Since: since}
+}
+
+// Deprecation records that a variable was read from a deprecated key, see
+// [Env.WithAliases] and [Loader.Deprecations].
+type Deprecation struct {
+	// Key is the deprecated key the value was read from.
+	Key string
+
+	// Replacement is the current key of the variable.
+	Replacement string
+
+	// Since is the [Alias.Since] of the deprecated key, if any.
+	Since string
+
+	// Origin is the layer the value was read from.
+	Origin Origin
+}
+
+// String describes the deprecation, telling which key to use instead.
+func (d Deprecation) String() string {
+	s := fmt.Sprintf("%s is deprecated, use %s instead", d.Key, d.Replacement)
+	if d.Since != "" {
+		s = fmt.Sprintf("%s is deprecated since %s, use %s instead", d.Key, d.Since, d.Replacement)
+	}
+
+	return s
+}
+
+// WithAliases declares deprecated keys of the environment variable, tried in
+// order when it is not set under its own key. A value found under an alias is
+// used as if it was set under the current key, and the use of the deprecated
+// key is recorded by the [Loader], see [Loader.Deprecations], so that it can
+// be logged and counted. If the current key and an alias are both set to
+// different values, it fails with [errors.ErrConflictingEnv], as the intended
+// value cannot be known. WithAliases must be called before [Env.WithDefault]
+// and the other validating methods. It returns the same [Env] instance to
+// enable method chaining.
+func (e Env) WithAliases(aliases ...Alias) Env {
+	for _, alias := range aliases {
+		ae := e.loader.NewEnv(alias.Key)
+		if ae.value == "" {
+			continue
+		}
+
+		if e.value == "" {
+			e.value, e.origin = ae.value, ae.origin
+			e.secret = e.secret || ae.secret
+
+			e.loader.recordDeprecation(Deprecation{
+				Key:         alias.Key,
+				Replacement: e.key,
+				Since:       alias.Since,
+				Origin:      ae.origin,
+			})
+
+			continue
+		}
+
+		if ae.value != e.value {
+			e.fail(e.newError(errors.ErrConflictingEnv).
+				WithField("alias", alias.Key), fmt.Sprintf("a single value for %s and its deprecated alias %s", e.key, alias.Key))
+			break
+		}
+	}
+
+	return e
+}
+
+// recordDeprecation records the use of a deprecated key, for
+// [Loader.Deprecations]. Nothing is recorded by a nil [*Loader].
+func (l *Loader) recordDeprecation(d Deprecation) {
+	if l == nil {
+		return
+	}
+
+	l.deprecations = append(l.deprecations, d)
+}
+
+// Deprecations returns the deprecated keys the values of the [Loader] were
+// read from, in the order they were read.
+func (l *Loader) Deprecations() []Deprecation {
+	if l == nil {
+		return nil
+	}
+
+	return l.deprecations
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEnvAliases verifies that deprecated keys are read when the current key
+// is not set, and that their use is recorded by the [Loader].
+func TestEnvAliases(t *testing.T) {
+	t.Setenv("SE_GA_TEST_OLD_TIMEOUT", "30s")
+
+	l := NewLoader()
+	aliases := []Alias{{Key: "SE_GA_TEST_OLDER_TIMEOUT"}, {Key: "SE_GA_TEST_OLD_TIMEOUT", Since: "v1.2"}}
+
+	e := l.NewEnv("SE_GA_TEST_TIMEOUT").WithAliases(aliases...).WithDefault("60s")
+	assert.Equal(t, "30s", e.GetString())
+	assert.Equal(t, OriginEnv, e.Origin())
+
+	assert.NoError(t, l.Err())
+	assert.Equal(t, []Deprecation{{
+		Key:         "SE_GA_TEST_OLD_TIMEOUT",
+		Replacement: "SE_GA_TEST_TIMEOUT",
+		Since:       "v1.2",
+		Origin:      OriginEnv,
+	}}, l.Deprecations())
+	assert.Equal(t, "SE_GA_TEST_OLD_TIMEOUT is deprecated since v1.2, use SE_GA_TEST_TIMEOUT instead", l.Deprecations()[0].String())
+
+	t.Setenv("SE_GA_TEST_TIMEOUT", "30s")
+
+	l = NewLoader()
+	assert.Equal(t, "30s", l.NewEnv("SE_GA_TEST_TIMEOUT").WithAliases(aliases...).GetString())
+	assert.NoError(t, l.Err())
+	assert.Empty(t, l.Deprecations())
+}
+
+// TestEnvAliasesConflict verifies that setting both the current key and an
+// alias to different values fails with [errors.ErrConflictingEnv].
+func TestEnvAliasesConflict(t *testing.T) {
+	t.Setenv("SE_GA_TEST_TIMEOUT", "30s")
+	t.Setenv("SE_GA_TEST_OLD_TIMEOUT", "45s")
+
+	err := recoverError(func() { NewEnv("SE_GA_TEST_TIMEOUT").WithAliases(ParseAlias("SE_GA_TEST_OLD_TIMEOUT")) })
+	assert.True(t, errors.Is(err, errors.ErrConflictingEnv))
+
+	var c struct {
+		Timeout string `env:"SE_GA_TEST_TIMEOUT" aliases:"SE_GA_TEST_OLD_TIMEOUT@v1.2"`
+	}
+
+	var le *LoadError
+	assert.True(t, errors.As(Bind(&c), &le))
+	assert.Equal(t, "SE_GA_TEST_TIMEOUT", le.Failures[0].Key)
+	assert.Contains(t, le.Failures[0].Expected, "deprecated alias SE_GA_TEST_OLD_TIMEOUT")
+}
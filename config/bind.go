@@ -0,0 +1,414 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stellarentropy/gravity-assist-common/consts"
+	"github.com/stellarentropy/gravity-assist-common/utils"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+)
+
+// Struct tags understood by [Bind] and [Loader.Bind].
+const (
+	// TagEnv names the environment variable a field is read from, without the
+	// prefix of the enclosing structs.
+	TagEnv = "env"
+
+	// TagDefault holds the value used when the variable is not set, see
+	// [Env.WithDefault]. The value used under a given [Profile] is held by
+	// the tag named after TagDefault and the profile, such as default.prod,
+	// see [Env.WithProfileDefault].
+	TagDefault = "default"
+
+	// TagOptions holds the comma-separated list of accepted values, see
+	// [Env.WithOptions].
+	TagOptions = "options"
+
+	// TagRequired marks the variable as required when set to "true", see
+	// [Env.WithRequired].
+	TagRequired = "required"
+
+	// TagSecret marks the variable as holding a secret when set to "true", see
+	// [Env.WithSecret].
+	TagSecret = "secret"
+
+	// TagMin and TagMax bound the value of integer fields, see
+	// [Env.WithIntInRange], and of float64 fields, see [InRange], whose bounds
+	// may be fractional. Both must be set for the range to be enforced.
+	TagMin = "min"
+	TagMax = "max"
+
+	// TagKind selects a specialized getter for string and int fields, such as
+	// [Env.GetAddress] or [Env.GetPort]. See the Kind constants.
+	TagKind = "kind"
+
+	// TagRequiredIf makes the variable required when another variable is set
+	// to one of the given values, written as KEY=value1|value2, see
+	// [Env.WithRequiredIf]. The key is not prefixed.
+	TagRequiredIf = "requiredif"
+
+	// TagDescription holds a human-readable description of the variable, used
+	// by [Describe] to document it.
+	TagDescription = "description"
+
+	// TagPrefix holds the prefix prepended to the keys of the fields of a
+	// nested struct.
+	TagPrefix = "prefix"
+
+	// TagSeparator holds the separator of the elements of slice fields and of
+	// the entries of map fields, [DefaultSeparator] if not set, see
+	// [Env.WithSeparator].
+	TagSeparator = "separator"
+
+	// TagAliases holds the comma-separated list of deprecated keys of the
+	// variable, each written as KEY or KEY@since, see [Env.WithAliases] and
+	// [ParseAlias]. The keys are not prefixed.
+	TagAliases = "aliases"
+
+	// TagKeyValueSeparator holds the separator of the keys and values of map
+	// fields, [DefaultKeyValueSeparator] if not set, see
+	// [Env.WithKeyValueSeparator].
+	TagKeyValueSeparator = "kvseparator"
+)
+
+// Kinds accepted by the [TagKind] tag, each selecting the [Env] getter of the
+// same name.
+const (
+	KindPort              = "port"
+	KindAddress           = "address"
+	KindURL               = "url"
+	KindURLPath           = "urlpath"
+	KindFile              = "file"
+	KindFileOrCreate      = "file-or-create"
+	KindDirectory         = "directory"
+	KindDirectoryOrCreate = "directory-or-create"
+	KindHostname          = "hostname"
+)
+
+// durationType is the [reflect.Type] of [time.Duration], which is read with
+// [Env.GetDuration] rather than as a plain integer.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Collection types read with the list and map getters of [Env].
+var (
+	stringSliceType   = reflect.TypeOf([]string(nil))
+	intSliceType      = reflect.TypeOf([]int(nil))
+	durationSliceType = reflect.TypeOf([]time.Duration(nil))
+	stringMapType     = reflect.TypeOf(map[string]string(nil))
+	cidrSliceType     = reflect.TypeOf([]netip.Prefix(nil))
+)
+
+// Typed values read with the network and size getters of [Env].
+var (
+	hostPortType = reflect.TypeOf(HostPort{})
+	urlType      = reflect.TypeOf((*url.URL)(nil))
+	byteSizeType = reflect.TypeOf(ByteSize(0))
+)
+
+// Bind fills the struct pointed to by v from the environment, according to the
+// tags of its fields, and returns a [*LoadError] listing every problem found.
+// See [Loader.Bind] for the supported tags and types.
+func Bind(v any) error {
+	l := NewLoader()
+	l.Bind(v)
+
+	return l.Err()
+}
+
+// Bind fills the struct pointed to by v from the environment, according to the
+// tags of its fields, recording every validation failure in the [Loader]. Each
+// field tagged with [TagEnv] is read through an [Env], configured from the
+// [TagSecret], [TagAliases], profile-specific [TagDefault], [TagDefault],
+// [TagSeparator], [TagKeyValueSeparator], [TagOptions], [TagRequired],
+// [TagRequiredIf], [TagMin] and [TagMax] tags in that order, and then parsed
+// according to its type: string, bool, int, float64, [time.Duration],
+// [HostPort], [ByteSize], absolute URLs as [*url.URL], slices of strings,
+// ints, durations and CIDR prefixes, and maps of strings are supported, and
+// string or int fields may select a specialized getter through [TagKind].
+// Untagged struct fields are bound recursively, with the keys of their fields
+// prefixed by their [TagPrefix] tag. Other fields are left untouched.
+//
+// Bind panics with [errors.ErrInvalidBinding] if v is not a non-nil pointer to
+// a struct or if a tagged field has an unsupported type or tag, as these are
+// programming errors rather than configuration problems.
+func (l *Loader) Bind(v any) {
+	walkFields(structValue(v), "", l.bindField)
+}
+
+// structValue returns the struct pointed to by v, panicking with
+// [errors.ErrInvalidBinding] if v is not a non-nil pointer to a struct.
+func structValue(v any) reflect.Value {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		panic(errors.NewError(errors.ErrInvalidBinding).
+			WithSource(consts.SE).
+			WithField("type", fmt.Sprintf("%T", v)))
+	}
+
+	return rv.Elem()
+}
+
+// walkFields calls fn for every exported field of the struct rv tagged with
+// [TagEnv], with the key of its variable prefixed by prefix. Untagged struct
+// fields are walked recursively, adding their [TagPrefix] tag to the prefix.
+func walkFields(rv reflect.Value, prefix string, fn func(fv reflect.Value, f reflect.StructField, key string)) {
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		key := utils.GetStructTag(f, TagEnv)
+
+		if key == "" {
+			if f.Type.Kind() == reflect.Struct {
+				walkFields(rv.Field(i), prefix+utils.GetStructTag(f, TagPrefix), fn)
+			}
+			continue
+		}
+
+		fn(rv.Field(i), f, prefix+key)
+	}
+}
+
+// bindField reads the variable with the given key into the field fv, as
+// described by the tags of f.
+func (l *Loader) bindField(fv reflect.Value, f reflect.StructField, key string) {
+	e := l.NewEnv(key)
+
+	if tagBool(f, TagSecret) {
+		e = e.WithSecret()
+	}
+
+	if aliases := tagAliases(f); len(aliases) > 0 {
+		e = e.WithAliases(aliases...)
+	}
+
+	for p, def := range tagProfileDefaults(f) {
+		e = e.WithProfileDefault(p, def)
+	}
+
+	if def, ok := f.Tag.Lookup(TagDefault); ok {
+		e = e.WithDefault(def)
+	}
+
+	switch f.Type {
+	case stringSliceType, intSliceType, durationSliceType, cidrSliceType:
+		e = e.WithSeparator(tagOr(f, TagSeparator, DefaultSeparator))
+	case stringMapType:
+		e = e.WithSeparator(tagOr(f, TagSeparator, DefaultSeparator)).
+			WithKeyValueSeparator(tagOr(f, TagKeyValueSeparator, DefaultKeyValueSeparator))
+	}
+
+	if opts := utils.GetStructTag(f, TagOptions); opts != "" {
+		e = e.WithOptions(strings.Split(opts, ",")...)
+	}
+
+	if tagBool(f, TagRequired) {
+		e = e.WithRequired()
+	}
+
+	if other, values, ok := tagRequiredIf(f); ok {
+		e = e.WithRequiredIf(other, values)
+	}
+
+	l.recordOrigin(key, e.Origin())
+
+	kind := utils.GetStructTag(f, TagKind)
+
+	switch {
+	case f.Type == durationType && kind == "":
+		fv.SetInt(int64(e.GetDuration()))
+
+	case f.Type == stringSliceType && kind == "":
+		fv.Set(reflect.ValueOf(e.GetStringSlice()))
+
+	case f.Type == intSliceType && kind == "":
+		fv.Set(reflect.ValueOf(e.GetIntSlice()))
+
+	case f.Type == durationSliceType && kind == "":
+		fv.Set(reflect.ValueOf(e.GetDurationSlice()))
+
+	case f.Type == stringMapType && kind == "":
+		fv.Set(reflect.ValueOf(e.GetStringMap()))
+
+	case f.Type == cidrSliceType && kind == "":
+		fv.Set(reflect.ValueOf(e.GetCIDRs()))
+
+	case f.Type == hostPortType && kind == "":
+		fv.Set(reflect.ValueOf(e.GetHostPort()))
+
+	case f.Type == urlType && kind == "":
+		fv.Set(reflect.ValueOf(e.GetAbsoluteURL()))
+
+	case f.Type == byteSizeType && kind == "":
+		fv.SetInt(int64(e.GetByteSize()))
+
+	case f.Type.Kind() == reflect.String:
+		fv.SetString(bindString(e, f, kind))
+
+	case f.Type.Kind() == reflect.Int:
+		min, max, ok := tagRange(f)
+		if ok {
+			e = e.WithIntInRange(min, max)
+		}
+
+		switch kind {
+		case "":
+			fv.SetInt(int64(e.GetInt()))
+		case KindPort:
+			fv.SetInt(int64(e.GetPort()))
+		default:
+			panic(invalidBinding(f, key))
+		}
+
+	case f.Type.Kind() == reflect.Bool && kind == "":
+		fv.SetBool(e.GetBool())
+
+	case f.Type.Kind() == reflect.Float64 && kind == "":
+		var validators []Validator[float64]
+		if min, max, ok := tagFloatRange(f); ok {
+			validators = append(validators, InRange(min, max))
+		}
+
+		fv.SetFloat(Get(e, Float64Parser, validators...))
+
+	default:
+		panic(invalidBinding(f, key))
+	}
+}
+
+// bindString reads e with the getter selected by kind.
+func bindString(e Env, f reflect.StructField, kind string) string {
+	switch kind {
+	case "":
+		return e.GetString()
+	case KindAddress:
+		return e.GetAddress()
+	case KindURL:
+		return e.GetURL()
+	case KindURLPath:
+		return e.GetURLPath()
+	case KindFile:
+		return e.GetFile()
+	case KindFileOrCreate:
+		return e.GetFileOrCreate()
+	case KindDirectory:
+		return e.GetDirectory()
+	case KindDirectoryOrCreate:
+		return e.GetDirectoryOrCreate()
+	case KindHostname:
+		return e.GetHostname()
+	}
+
+	panic(invalidBinding(f, e.key))
+}
+
+// tagOr returns the given tag of f, or def if it is not set.
+func tagOr(f reflect.StructField, tag string, def string) string {
+	if v, ok := f.Tag.Lookup(tag); ok {
+		return v
+	}
+
+	return def
+}
+
+// tagBool reports whether the given tag of f is set to a true boolean value.
+func tagBool(f reflect.StructField, tag string) bool {
+	b, _ := strconv.ParseBool(utils.GetStructTag(f, tag))
+
+	return b
+}
+
+// tagRange returns the bounds held by the [TagMin] and [TagMax] tags of f, and
+// whether both are set.
+func tagRange(f reflect.StructField) (int, int, bool) {
+	smin, okMin := f.Tag.Lookup(TagMin)
+	smax, okMax := f.Tag.Lookup(TagMax)
+
+	if !okMin || !okMax {
+		return 0, 0, false
+	}
+
+	min, err := strconv.Atoi(smin)
+	if err != nil {
+		panic(invalidBinding(f, utils.GetStructTag(f, TagEnv)))
+	}
+
+	max, err := strconv.Atoi(smax)
+	if err != nil {
+		panic(invalidBinding(f, utils.GetStructTag(f, TagEnv)))
+	}
+
+	return min, max, true
+}
+
+// tagFloatRange returns the bounds held by the [TagMin] and [TagMax] tags of
+// f, which may be fractional, and whether both are set.
+func tagFloatRange(f reflect.StructField) (float64, float64, bool) {
+	smin, okMin := f.Tag.Lookup(TagMin)
+	smax, okMax := f.Tag.Lookup(TagMax)
+
+	if !okMin || !okMax {
+		return 0, 0, false
+	}
+
+	min, err := strconv.ParseFloat(smin, 64)
+	if err != nil {
+		panic(invalidBinding(f, utils.GetStructTag(f, TagEnv)))
+	}
+
+	max, err := strconv.ParseFloat(smax, 64)
+	if err != nil {
+		panic(invalidBinding(f, utils.GetStructTag(f, TagEnv)))
+	}
+
+	return min, max, true
+}
+
+// tagAliases returns the aliases held by the [TagAliases] tag of f.
+func tagAliases(f reflect.StructField) []Alias {
+	var aliases []Alias
+
+	for _, s := range strings.Split(utils.GetStructTag(f, TagAliases), ",") {
+		if a := ParseAlias(s); a.Key != "" {
+			aliases = append(aliases, a)
+		}
+	}
+
+	return aliases
+}
+
+// tagRequiredIf returns the key and the values held by the [TagRequiredIf] tag
+// of f, and whether it is set.
+func tagRequiredIf(f reflect.StructField) (string, []string, bool) {
+	tag := utils.GetStructTag(f, TagRequiredIf)
+	if tag == "" {
+		return "", nil, false
+	}
+
+	key, values, ok := strings.Cut(tag, "=")
+	if !ok || key == "" {
+		panic(invalidBinding(f, utils.GetStructTag(f, TagEnv)))
+	}
+
+	return key, strings.Split(values, "|"), true
+}
+
+// invalidBinding creates the error reported for a field that cannot be bound.
+func invalidBinding(f reflect.StructField, key string) *errors.Error {
+	return errors.NewError(errors.ErrInvalidBinding).
+		WithSource(consts.SE).
+		WithField("env", key).
+		WithField("field", f.Name).
+		WithField("type", f.Type.String())
+}
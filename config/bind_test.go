@@ -0,0 +1,109 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// listenerConfig is a nested struct bound with a prefix by [testConfig].
+type listenerConfig struct {
+	Address string        `env:"LISTEN_ADDRESS" default:"0.0.0.0" kind:"address"`
+	Port    int           `env:"LISTEN_PORT" default:"8080" kind:"port"`
+	Timeout time.Duration `env:"TIMEOUT" default:"30s"`
+}
+
+// testConfig exercises every type and tag supported by [Bind].
+type testConfig struct {
+	Name      string  `env:"SE_GA_TEST_NAME" required:"true"`
+	Format    string  `env:"SE_GA_TEST_FORMAT" default:"color" options:"text,color,json"`
+	Enabled   bool    `env:"SE_GA_TEST_ENABLED" default:"true"`
+	Workers   int     `env:"SE_GA_TEST_WORKERS" default:"4" min:"1" max:"64"`
+	Ratio     float64 `env:"SE_GA_TEST_RATIO" default:"0.5" min:"0.1" max:"0.9"`
+	Directory string  `env:"SE_GA_TEST_DIRECTORY" kind:"directory"`
+	Endpoint  string  `env:"SE_GA_TEST_ENDPOINT" kind:"url"`
+
+	Public  listenerConfig `prefix:"SE_GA_TEST_PUBLIC_"`
+	Private listenerConfig `prefix:"SE_GA_TEST_PRIVATE_"`
+
+	untagged string
+}
+
+// TestBind verifies that every supported type is read from the environment,
+// falling back to the defaults, and that nested structs use their prefix.
+func TestBind(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Setenv("SE_GA_TEST_NAME", "agent")
+	t.Setenv("SE_GA_TEST_ENABLED", "false")
+	t.Setenv("SE_GA_TEST_WORKERS", "16")
+	t.Setenv("SE_GA_TEST_DIRECTORY", dir)
+	t.Setenv("SE_GA_TEST_ENDPOINT", "https://pcm.example.com")
+	t.Setenv("SE_GA_TEST_PRIVATE_LISTEN_ADDRESS", "127.0.0.1")
+	t.Setenv("SE_GA_TEST_PRIVATE_LISTEN_PORT", "9090")
+	t.Setenv("SE_GA_TEST_PRIVATE_TIMEOUT", "5s")
+
+	var c testConfig
+	assert.NoError(t, Bind(&c))
+
+	assert.Equal(t, "agent", c.Name)
+	assert.Equal(t, "color", c.Format)
+	assert.False(t, c.Enabled)
+	assert.Equal(t, 16, c.Workers)
+	assert.Equal(t, 0.5, c.Ratio)
+	assert.Equal(t, dir, c.Directory)
+	assert.Equal(t, "https://pcm.example.com", c.Endpoint)
+	assert.Equal(t, listenerConfig{Address: "0.0.0.0", Port: 8080, Timeout: 30 * time.Second}, c.Public)
+	assert.Equal(t, listenerConfig{Address: "127.0.0.1", Port: 9090, Timeout: 5 * time.Second}, c.Private)
+	assert.Empty(t, c.untagged)
+}
+
+// TestBindCollectsFailures verifies that [Bind] reports every invalid or
+// missing variable at once, using the keys prefixed by nested structs.
+func TestBindCollectsFailures(t *testing.T) {
+	t.Setenv("SE_GA_TEST_FORMAT", "yaml")
+	t.Setenv("SE_GA_TEST_WORKERS", "128")
+	t.Setenv("SE_GA_TEST_RATIO", "0.95")
+	t.Setenv("SE_GA_TEST_PUBLIC_LISTEN_PORT", "http")
+
+	var c testConfig
+	err := Bind(&c)
+
+	var le *LoadError
+	assert.True(t, errors.As(err, &le))
+
+	var keys []string
+	for _, f := range le.Failures {
+		keys = append(keys, f.Key)
+	}
+
+	assert.Equal(t, []string{
+		"SE_GA_TEST_NAME",
+		"SE_GA_TEST_FORMAT",
+		"SE_GA_TEST_WORKERS",
+		"SE_GA_TEST_RATIO",
+		"SE_GA_TEST_PUBLIC_LISTEN_PORT",
+	}, keys)
+}
+
+// TestBindInvalid verifies that binding something other than a pointer to a
+// struct, or a field of an unsupported type, panics with
+// [errors.ErrInvalidBinding].
+func TestBindInvalid(t *testing.T) {
+	var c testConfig
+
+	err := recoverError(func() { _ = Bind(c) })
+	assert.True(t, errors.Is(err, errors.ErrInvalidBinding))
+
+	err = recoverError(func() { _ = Bind(nil) })
+	assert.True(t, errors.Is(err, errors.ErrInvalidBinding))
+
+	var u struct {
+		Flags []bool `env:"SE_GA_TEST_FLAGS"`
+	}
+
+	err = recoverError(func() { _ = Bind(&u) })
+	assert.True(t, errors.Is(err, errors.ErrInvalidBinding))
+}
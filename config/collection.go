@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+)
+
+// DefaultSeparator separates the elements of lists and the entries of maps
+// read from an environment variable, unless changed with [Env.WithSeparator].
+const DefaultSeparator = ","
+
+// DefaultKeyValueSeparator separates the key from the value of each entry of
+// a map read from an environment variable, unless changed with
+// [Env.WithKeyValueSeparator].
+const DefaultKeyValueSeparator = "="
+
+// WithSeparator declares the environment variable as holding a list whose
+// elements are separated by sep, such as "a,b,c" for ",". It must be called
+// before [Env.WithOptions] for the options to apply to each element. The list
+// getters use [DefaultSeparator] when it is not called. It returns the same
+// [Env] instance to enable method chaining.
+func (e Env) WithSeparator(sep string) Env {
+	e.separator = sep
+
+	return e
+}
+
+// WithKeyValueSeparator declares the environment variable as holding a map
+// whose entries are separated by the separator of [Env.WithSeparator] and
+// whose keys are separated from their value by sep, such as "a=1,b=2" for
+// "=". It must be called before [Env.WithOptions] for the options to apply to
+// each key. [Env.GetStringMap] uses [DefaultKeyValueSeparator] when it is not
+// called. It returns the same [Env] instance to enable method chaining.
+func (e Env) WithKeyValueSeparator(sep string) Env {
+	if e.separator == "" {
+		e.separator = DefaultSeparator
+	}
+
+	e.kvSeparator = sep
+
+	return e
+}
+
+// GetStringSlice splits the value of the environment variable into a list of
+// strings, trimming the whitespace around each element and dropping empty
+// ones. It returns nil if the variable is unset or empty.
+func (e Env) GetStringSlice() []string {
+	return GetSlice(e, StringParser)
+}
+
+// GetIntSlice splits the value of the environment variable into a list of
+// integers, as [Env.GetStringSlice] does. If an element is not a valid
+// integer, it panics.
+func (e Env) GetIntSlice() []int {
+	return GetSlice(e, IntParser)
+}
+
+// GetDurationSlice splits the value of the environment variable into a list
+// of durations, as [Env.GetStringSlice] does. If an element is not a valid
+// duration, it panics.
+func (e Env) GetDurationSlice() []time.Duration {
+	return GetSlice(e, DurationParser)
+}
+
+// GetStringMap splits the value of the environment variable into a map, such
+// as "a=1,b=2", trimming the whitespace around each key and value. It returns
+// nil if the variable is unset or empty. If an entry has no key separator or
+// an empty key, or if a key is repeated, it panics.
+func (e Env) GetStringMap() map[string]string {
+	e = e.asList()
+	if e.kvSeparator == "" {
+		e.kvSeparator = DefaultKeyValueSeparator
+	}
+
+	elems := e.elements()
+	if len(elems) == 0 {
+		return nil
+	}
+
+	expected := fmt.Sprintf("key%svalue pairs separated by %q", e.kvSeparator, e.separator)
+	m := make(map[string]string, len(elems))
+
+	for _, elem := range elems {
+		k, v, ok := strings.Cut(elem, e.kvSeparator)
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+
+		if !ok || k == "" {
+			e.fail(e.newElementError(elem), expected)
+			return nil
+		}
+
+		if _, dup := m[k]; dup {
+			e.fail(e.newElementError(elem).
+				WithField("duplicate", k), expected+" with unique keys")
+			return nil
+		}
+
+		m[k] = v
+	}
+
+	return m
+}
+
+// asList returns the [Env] with [DefaultSeparator] if no separator was set.
+func (e Env) asList() Env {
+	if e.separator == "" {
+		e.separator = DefaultSeparator
+	}
+
+	return e
+}
+
+// elements splits the value of the environment variable with its separator,
+// trimming the whitespace around each element and dropping empty ones.
+func (e Env) elements() []string {
+	var elems []string
+
+	for _, elem := range strings.Split(e.value, e.separator) {
+		if elem = strings.TrimSpace(elem); elem != "" {
+			elems = append(elems, elem)
+		}
+	}
+
+	return elems
+}
+
+// newElementError creates a structured [*errors.Error] for an invalid element
+// of a list or map, carrying the element along with the key and value of the
+// [Env] instance. The element of a secret [Env] is attached as a sensitive
+// field.
+func (e Env) newElementError(elem string) *errors.Error {
+	err := e.newError(errors.ErrInvalidEnv).
+		WithField("separator", e.separator)
+
+	if e.IsSecret() {
+		return err.WithSensitiveField("element", elem)
+	}
+
+	return err.WithField("element", elem)
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEnvCollections verifies that lists and maps are split with the default
+// or configured separators, with the whitespace around elements trimmed.
+func TestEnvCollections(t *testing.T) {
+	t.Setenv("SE_GA_TEST_BUCKETS", " raw , processed,, archive ")
+	t.Setenv("SE_GA_TEST_PORTS", "8080;8443")
+	t.Setenv("SE_GA_TEST_BACKOFF", "100ms, 1s,5s")
+	t.Setenv("SE_GA_TEST_LABELS", "team = payments, tier=gold")
+	t.Setenv("SE_GA_TEST_ROUTES", "eu:pcm-eu|us:pcm-us")
+
+	assert.Equal(t, []string{"raw", "processed", "archive"}, NewEnv("SE_GA_TEST_BUCKETS").GetStringSlice())
+	assert.Equal(t, []int{8080, 8443}, NewEnv("SE_GA_TEST_PORTS").WithSeparator(";").GetIntSlice())
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, time.Second, 5 * time.Second}, NewEnv("SE_GA_TEST_BACKOFF").GetDurationSlice())
+	assert.Equal(t, map[string]string{"team": "payments", "tier": "gold"}, NewEnv("SE_GA_TEST_LABELS").GetStringMap())
+	assert.Equal(t, map[string]string{"eu": "pcm-eu", "us": "pcm-us"}, NewEnv("SE_GA_TEST_ROUTES").
+		WithSeparator("|").
+		WithKeyValueSeparator(":").
+		WithOptions("eu", "us").
+		GetStringMap())
+
+	assert.Nil(t, NewEnv("SE_GA_TEST_UNSET").GetStringSlice())
+	assert.Nil(t, NewEnv("SE_GA_TEST_UNSET").GetStringMap())
+	assert.Equal(t, []string{"a", "b"}, NewEnv("SE_GA_TEST_UNSET").WithDefault("a,b").GetStringSlice())
+}
+
+// TestEnvCollectionsInvalid verifies that invalid elements panic, or are
+// collected by a [Loader], and that options apply to each element.
+func TestEnvCollectionsInvalid(t *testing.T) {
+	t.Setenv("SE_GA_TEST_PORTS", "8080,http")
+	t.Setenv("SE_GA_TEST_BACKOFF", "1s,soon")
+	t.Setenv("SE_GA_TEST_LABELS", "team=payments,tier")
+	t.Setenv("SE_GA_TEST_FORMATS", "json, yaml")
+
+	for _, fn := range []func(){
+		func() { NewEnv("SE_GA_TEST_PORTS").GetIntSlice() },
+		func() { NewEnv("SE_GA_TEST_BACKOFF").GetDurationSlice() },
+		func() { NewEnv("SE_GA_TEST_LABELS").GetStringMap() },
+		func() { NewEnv("SE_GA_TEST_FORMATS").WithSeparator(",").WithOptions("json", "text") },
+	} {
+		err := recoverError(fn)
+		assert.True(t, errors.Is(err, errors.ErrInvalidEnv))
+	}
+
+	err := recoverError(func() { NewEnv("SE_GA_TEST_FORMATS").WithSeparator(",").WithOptions("json", "text") })
+	element, _ := errors.Lookup[string](err, "element")
+	assert.Equal(t, "yaml", element)
+
+	l := NewLoader()
+	assert.Nil(t, l.NewEnv("SE_GA_TEST_PORTS").GetIntSlice())
+	l.NewEnv("SE_GA_TEST_LABELS").GetStringMap()
+	l.NewEnv("SE_GA_TEST_FORMATS").WithSeparator(",").WithOptions("json", "text").GetStringSlice()
+
+	assert.Len(t, l.Failures(), 3)
+	assert.Equal(t, `integers separated by ","`, l.Failures()[0].Expected)
+}
+
+// TestBindCollections verifies that slice and map fields are bound with the
+// separators of their tags.
+func TestBindCollections(t *testing.T) {
+	var c struct {
+		Buckets []string          `env:"SE_GA_TEST_BUCKETS" default:"raw,processed" options:"raw,processed,archive"`
+		Ports   []int             `env:"SE_GA_TEST_PORTS" separator:";"`
+		Backoff []time.Duration   `env:"SE_GA_TEST_BACKOFF" default:"1s,2s"`
+		Labels  map[string]string `env:"SE_GA_TEST_LABELS" separator:"|" kvseparator:":"`
+	}
+
+	l := NewMapLoader(map[string]string{
+		"SE_GA_TEST_PORTS":  "80; 443",
+		"SE_GA_TEST_LABELS": "team:payments|tier:gold",
+	})
+	l.Bind(&c)
+	assert.NoError(t, l.Err())
+
+	assert.Equal(t, []string{"raw", "processed"}, c.Buckets)
+	assert.Equal(t, []int{80, 443}, c.Ports)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, c.Backoff)
+	assert.Equal(t, map[string]string{"team": "payments", "tier": "gold"}, c.Labels)
+
+	entries := DumpEntries(&c, l.Origins())
+	assert.Equal(t, []string{"1s", "2s"}, entries[2].Value)
+}
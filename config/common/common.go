@@ -1,3 +1,6 @@
+//go:generate go run ../../cmd/configdoc -format markdown -out ../../docs/config.md
+//go:generate go run ../../cmd/configdoc -format json -out ../../docs/config.json
+
 package common_config
 
 import (
@@ -12,153 +15,140 @@ import (
 // resources, integration with services, and security protocols. The structure
 // facilitates the agent's ability to adapt to various deployment contexts by
 // utilizing environmental variables to customize its behavior accordingly.
+// Each field is bound to its environment variable through struct tags, see
+// [config.Loader.Bind], with defaults that depend on the [config.Profile]
+// selected by [config.ProfileKey]: production gets JSON logs and ratio-based
+// trace sampling, while tests export neither traces nor metrics.
 type CommonConfig struct {
-	ServiceName string
+	Profile string `env:"SE_GA_PROFILE" default:"dev" options:"dev,test,staging,prod" required:"true" description:"Deployment profile selecting the defaults of the other variables."`
+
+	ConfigFile           string        `env:"SE_GA_CONFIG_FILE" kind:"file" description:"Optional YAML, JSON or .env file layered between the defaults and the environment variables."`
+	ConfigReloadInterval time.Duration `env:"SE_GA_CONFIG_RELOAD_INTERVAL" default:"10s" required:"true" description:"Interval at which the configuration file is checked for changes once reloading is started, 0s to only reload on SIGHUP."`
+
+	ServiceName string `env:"SE_GA_SERVICE_NAME" default:"gravity-assist-common" required:"true" description:"Name of the service, reported with traces and metrics."`
+
+	EnableMetricCollection bool          `env:"SE_GA_ENABLE_METRIC_COLLECTION" default:"true" default.test:"false" required:"true" description:"Whether metrics are recorded and exported."`
+	MetricExportInterval   time.Duration `env:"SE_GA_METRIC_EXPORT_INTERVAL" default:"10s" required:"true" description:"Interval at which metrics are exported."`
+
+	EnableTraceCollection bool    `env:"SE_GA_ENABLE_TRACE_COLLECTION" default:"true" default.test:"false" required:"true" description:"Whether traces are recorded and exported."`
+	TraceSampler          string  `env:"SE_GA_TRACE_SAMPLER" default:"always" default.test:"never" default.staging:"traceIdRatio" default.prod:"traceIdRatio" options:"always,never,traceIdRatio" required:"true" description:"Sampler deciding which traces are recorded."`
+	TraceIdRatio          float64 `env:"SE_GA_TRACE_ID_RATIO" default:"0.01" default.staging:"0.1" required:"true" min:"0" max:"1" description:"Fraction of traces recorded by the traceIdRatio sampler."`
+
+	GoogleProjectId string `env:"SE_GA_PROJECT_ID" default:"gravity-assist" required:"true" description:"Google Cloud project traces and metrics are exported to."`
+
+	DebugListenAddress string        `env:"SE_GA_DEBUG_LISTEN_ADDRESS" default:"127.0.0.1" required:"true" kind:"address" description:"Address the debug server listens on."`
+	DebugListenPort    int           `env:"SE_GA_DEBUG_LISTEN_PORT" default:"0" required:"true" kind:"port" description:"Port the debug server listens on, 0 for any free port."`
+	DebugReadTimeout   time.Duration `env:"SE_GA_DEBUG_READ_TIMEOUT" default:"60s" required:"true" description:"Maximum duration for reading a request to the debug server."`
+	DebugWriteTimeout  time.Duration `env:"SE_GA_DEBUG_WRITE_TIMEOUT" default:"60s" required:"true" description:"Maximum duration for writing a response of the debug server."`
+
+	MetricsListenAddress string        `env:"SE_GA_METRICS_LISTEN_ADDRESS" default:"0.0.0.0" required:"true" kind:"address" description:"Address the metrics server listens on."`
+	MetricsListenPort    int           `env:"SE_GA_METRICS_LISTEN_PORT" default:"9090" required:"true" kind:"port" description:"Port the metrics server listens on."`
+	MetricsReadTimeout   time.Duration `env:"SE_GA_METRICS_READ_TIMEOUT" default:"60s" required:"true" description:"Maximum duration for reading a request to the metrics server."`
+	MetricsWriteTimeout  time.Duration `env:"SE_GA_METRICS_WRITE_TIMEOUT" default:"60s" required:"true" description:"Maximum duration for writing a response of the metrics server."`
+
+	HealthListenAddress string        `env:"SE_GA_HEALTH_LISTEN_ADDRESS" default:"0.0.0.0" required:"true" kind:"address" description:"Address the health server listens on."`
+	HealthListenPort    int           `env:"SE_GA_HEALTH_LISTEN_PORT" default:"1234" required:"true" kind:"port" description:"Port the health server listens on."`
+	HealthReadTimeout   time.Duration `env:"SE_GA_HEALTH_READ_TIMEOUT" default:"60s" required:"true" description:"Maximum duration for reading a request to the health server."`
+	HealthWriteTimeout  time.Duration `env:"SE_GA_HEALTH_WRITE_TIMEOUT" default:"60s" required:"true" description:"Maximum duration for writing a response of the health server."`
+
+	GracefulShutdownTimeout time.Duration `env:"SE_GA_GRACEFUL_SHUTDOWN_TIMEOUT" aliases:"SE_GA_HEALTH_GRACEFUL_SHUTDOWN_TIMEOUT@2026-10" default:"60s" required:"true" description:"Maximum duration granted to servers and exporters to shut down gracefully."`
+
+	LogFormat string `env:"SE_GA_LOG_FORMAT" default:"color" default.test:"text" default.staging:"json" default.prod:"json" options:"text,color,json" required:"true" description:"Format of the log output."`
+	LogLevel  string `env:"SE_GA_LOG_LEVEL" default:"info" options:"trace,debug,info,warn,error" required:"true" description:"Minimum level of the logged messages."`
+
+	ErrorStackCapture string `env:"SE_GA_ERROR_STACK_CAPTURE" default:"none" options:"none,caller,full" required:"true" description:"Amount of call stack recorded when errors are created."`
+
+	origins      config.Origins
+	deprecations []config.Deprecation
+}
 
-	EnableMetricCollection bool
-	MetricExportInterval   time.Duration
+// init registers the variables of [CommonConfig], so that they are documented
+// in the configuration reference generated by cmd/configdoc.
+func init() {
+	config.Register("common", &CommonConfig{})
+}
+
+// Load reads the [CommonConfig] from the environment variables of the process
+// and the configuration file selected by [config.ConfigFileKey]. It returns a
+// [*config.LoadError] listing every problem at once if the configuration is
+// invalid. Nothing is read until Load is called, so that importing a package
+// never fails because of its configuration.
+func Load() (*CommonConfig, error) {
+	return load(config.NewLoader())
+}
+
+// MustLoad reads the [CommonConfig] like [Load], panicking with a
+// [*config.LoadError] listing every problem if it is invalid. It is meant to
+// be called once, early in the main function of a service.
+func MustLoad() *CommonConfig {
+	c, err := Load()
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// FromMap builds the [CommonConfig] from the given values only, applying the
+// defaults for missing keys, without touching the environment of the process.
+// It is meant to be used by tests, for example:
+//
+//	c, err := common_config.FromMap(map[string]string{"SE_GA_LOG_FORMAT": "json"})
+func FromMap(values map[string]string) (*CommonConfig, error) {
+	return load(config.NewMapLoader(values))
+}
+
+// NewReloader creates a [config.Reloadable] holding c, reloading it from the
+// environment variables and the configuration file with [Load] on SIGHUP or
+// when the file changes, once started with [config.Reloadable.Start]. Settings
+// that can change at runtime, such as the log level or the trace sampler,
+// must be read through [config.Reloadable.Get] or followed with
+// [config.Reloadable.Subscribe].
+func NewReloader(c *CommonConfig) *config.Reloadable[CommonConfig] {
+	return config.NewReloadable(c, Load, c.ConfigReloadInterval)
+}
 
-	EnableTraceCollection bool
-	TraceSampler          string
-	TraceIdRatio          float64
+// New binds the [CommonConfig] to the environment through the given
+// [config.Loader], which collects every validation failure instead of
+// panicking on the first one. The returned configuration must not be used
+// unless [config.Loader.Err] reports no error. A nil [config.Loader] makes the
+// first failure panic, as with [config.NewEnv].
+func New(l *config.Loader) *CommonConfig {
+	c := &CommonConfig{}
+	l.Bind(c)
 
-	GoogleProjectId string
+	c.origins = l.Origins()
+	c.deprecations = l.Deprecations()
 
-	DebugListenAddress string
-	DebugListenPort    int
-	DebugReadTimeout   time.Duration
-	DebugWriteTimeout  time.Duration
+	return c
+}
 
-	MetricsListenAddress string
-	MetricsListenPort    int
-	MetricsReadTimeout   time.Duration
-	MetricsWriteTimeout  time.Duration
+// load binds a new [CommonConfig] through the given [config.Loader],
+// returning a [*config.LoadError] listing every problem if it is invalid.
+func load(l *config.Loader) (*CommonConfig, error) {
+	c := New(l)
 
-	HealthListenAddress string
-	HealthListenPort    int
-	HealthReadTimeout   time.Duration
-	HealthWriteTimeout  time.Duration
+	if err := l.Err(); err != nil {
+		return nil, err
+	}
 
-	GracefulShutdownTimeout time.Duration
+	return c, nil
+}
+
+// Origins returns the layer each value of the configuration was read from,
+// keyed by variable.
+func (c *CommonConfig) Origins() config.Origins {
+	return c.origins
+}
 
-	LogFormat string
+// Deprecations returns the deprecated keys the configuration was read from,
+// which operators should rename.
+func (c *CommonConfig) Deprecations() []config.Deprecation {
+	return c.deprecations
 }
 
-// Common holds the configuration for an agent, encapsulating settings necessary
-// for network communication, worker pool management, storage access, and
-// service endpoint interactions, and includes security parameters to ensure
-// safe operations within its environment.
-var Common = &CommonConfig{
-	ServiceName: config.NewEnv("SE_GA_SERVICE_NAME").
-		WithDefault("gravity-assist-common").
-		WithRequired().
-		GetString(),
-
-	EnableMetricCollection: config.NewEnv("SE_GA_ENABLE_METRIC_COLLECTION").
-		WithDefault("true").
-		WithRequired().
-		GetBool(),
-
-	MetricExportInterval: config.NewEnv("SE_GA_METRIC_EXPORT_INTERVAL").
-		WithDefault("10s").
-		WithRequired().
-		GetDuration(),
-
-	EnableTraceCollection: config.NewEnv("SE_GA_ENABLE_TRACE_COLLECTION").
-		WithDefault("true").
-		WithRequired().
-		GetBool(),
-
-	TraceSampler: config.NewEnv("SE_GA_TRACE_SAMPLER").
-		WithDefault("always").
-		WithOptions("always", "never", "traceIdRatio").
-		WithRequired().
-		GetString(),
-
-	TraceIdRatio: config.NewEnv("SE_GA_TRACE_ID_RATIO").
-		WithDefault("0.01").
-		WithRequired().
-		GetFloat64(),
-
-	GoogleProjectId: config.NewEnv("SE_GA_PROJECT_ID").
-		WithDefault("gravity-assist").
-		WithRequired().
-		GetString(),
-
-	// region Debug
-	DebugListenAddress: config.NewEnv("SE_GA_DEBUG_LISTEN_ADDRESS").
-		WithDefault("127.0.0.1").
-		WithRequired().
-		GetAddress(),
-
-	DebugListenPort: config.NewEnv("SE_GA_DEBUG_LISTEN_PORT").
-		WithDefault("0").
-		WithRequired().
-		GetPort(),
-
-	DebugReadTimeout: config.NewEnv("SE_GA_DEBUG_READ_TIMEOUT").
-		WithDefault("60s").
-		WithRequired().
-		GetDuration(),
-
-	DebugWriteTimeout: config.NewEnv("SE_GA_DEBUG_WRITE_TIMEOUT").
-		WithDefault("60s").
-		WithRequired().
-		GetDuration(),
-	// endregion
-
-	// region Metrics
-	MetricsListenAddress: config.NewEnv("SE_GA_METRICS_LISTEN_ADDRESS").
-		WithDefault("0.0.0.0").
-		WithRequired().
-		GetAddress(),
-
-	MetricsListenPort: config.NewEnv("SE_GA_METRICS_LISTEN_PORT").
-		WithDefault("9090").
-		WithRequired().
-		GetPort(),
-
-	MetricsReadTimeout: config.NewEnv("SE_GA_METRICS_READ_TIMEOUT").
-		WithDefault("60s").
-		WithRequired().
-		GetDuration(),
-
-	MetricsWriteTimeout: config.NewEnv("SE_GA_METRICS_WRITE_TIMEOUT").
-		WithDefault("60s").
-		WithRequired().
-		GetDuration(),
-	// endregion
-
-	// region Health
-	HealthListenAddress: config.NewEnv("SE_GA_HEALTH_LISTEN_ADDRESS").
-		WithDefault("0.0.0.0").
-		WithRequired().
-		GetAddress(),
-
-	HealthListenPort: config.NewEnv("SE_GA_HEALTH_LISTEN_PORT").
-		WithDefault("1234").
-		WithRequired().
-		GetPort(),
-
-	HealthReadTimeout: config.NewEnv("SE_GA_HEALTH_READ_TIMEOUT").
-		WithDefault("60s").
-		WithRequired().
-		GetDuration(),
-
-	HealthWriteTimeout: config.NewEnv("SE_GA_HEALTH_WRITE_TIMEOUT").
-		WithDefault("60s").
-		WithRequired().
-		GetDuration(),
-	// endregion
-
-	GracefulShutdownTimeout: config.NewEnv("SE_GA_HEALTH_GRACEFUL_SHUTDOWN_TIMEOUT").
-		WithDefault("60s").
-		WithRequired().
-		GetDuration(),
-
-	LogFormat: config.NewEnv("SE_GA_LOG_FORMAT").
-		WithDefault("color").
-		WithOptions("text", "color", "json").
-		WithRequired().
-		GetString(),
+// Dump renders the effective configuration as JSON, with the source of each
+// value and the secrets masked, see [config.Dump].
+func (c *CommonConfig) Dump() ([]byte, error) {
+	return config.Dump(c, c.origins)
 }
@@ -0,0 +1,83 @@
+package common_config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellarentropy/gravity-assist-common/config"
+	"github.com/stellarentropy/gravity-assist-common/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFromMap verifies that a [CommonConfig] can be built from a map, with
+// the defaults applied to missing keys, regardless of the environment of the
+// process.
+func TestFromMap(t *testing.T) {
+	t.Setenv("SE_GA_LOG_FORMAT", "text")
+
+	c, err := FromMap(map[string]string{
+		"SE_GA_LOG_FORMAT":             "json",
+		"SE_GA_HEALTH_LISTEN_PORT":     "8081",
+		"SE_GA_TRACE_SAMPLER":          "traceIdRatio",
+		"SE_GA_TRACE_ID_RATIO":         "0.25",
+		"SE_GA_METRIC_EXPORT_INTERVAL": "1m",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "json", c.LogFormat)
+	assert.Equal(t, 8081, c.HealthListenPort)
+	assert.Equal(t, "traceIdRatio", c.TraceSampler)
+	assert.Equal(t, 0.25, c.TraceIdRatio)
+	assert.Equal(t, time.Minute, c.MetricExportInterval)
+	assert.Equal(t, "gravity-assist-common", c.ServiceName)
+	assert.Equal(t, 60*time.Second, c.GracefulShutdownTimeout)
+}
+
+// TestFromMapDeprecatedKey verifies that a deprecated key is still read, and
+// reported by [CommonConfig.Deprecations].
+func TestFromMapDeprecatedKey(t *testing.T) {
+	c, err := FromMap(map[string]string{
+		"SE_GA_HEALTH_GRACEFUL_SHUTDOWN_TIMEOUT": "15s",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 15*time.Second, c.GracefulShutdownTimeout)
+	assert.Len(t, c.Deprecations(), 1)
+	assert.Equal(t, "SE_GA_GRACEFUL_SHUTDOWN_TIMEOUT", c.Deprecations()[0].Replacement)
+}
+
+// TestFromMapProfile verifies that the prod profile applies its safe defaults
+// to the variables that are not set.
+func TestFromMapProfile(t *testing.T) {
+	c, err := FromMap(map[string]string{
+		"SE_GA_PROFILE": "prod",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "prod", c.Profile)
+	assert.Equal(t, "json", c.LogFormat)
+	assert.Equal(t, "traceIdRatio", c.TraceSampler)
+	assert.Equal(t, 0.01, c.TraceIdRatio)
+}
+
+// TestLoad verifies that [Load] reads the environment of the process and
+// reports every problem at once, while [MustLoad] panics with them.
+func TestLoad(t *testing.T) {
+	t.Setenv("SE_GA_LOG_FORMAT", "json")
+
+	c, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "json", c.LogFormat)
+
+	t.Setenv("SE_GA_LOG_FORMAT", "xml")
+	t.Setenv("SE_GA_HEALTH_LISTEN_PORT", "99999")
+	t.Setenv("SE_GA_TRACE_ID_RATIO", "1.5")
+
+	_, err = Load()
+
+	var le *config.LoadError
+	assert.True(t, errors.As(err, &le))
+	assert.Len(t, le.Failures, 3)
+
+	assert.Panics(t, func() { MustLoad() })
+}
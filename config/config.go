@@ -2,13 +2,10 @@ package config
 
 import (
 	"fmt"
-	"net"
-	"net/url"
-	"os"
-	"path/filepath"
-	"strconv"
+	"strings"
 	"time"
 
+	"github.com/stellarentropy/gravity-assist-common/consts"
 	"github.com/stellarentropy/gravity-assist-common/utils"
 
 	"github.com/stellarentropy/gravity-assist-common/errors"
@@ -20,33 +17,71 @@ import (
 // directories for file paths, and enforces constraints like integer ranges or
 // string option lists. Env methods can induce panics for validation failures or
 // missing mandatory variables, facilitating early configuration error
-// detection. The design encourages method chaining for concise configuration
-// expressions.
+// detection. When created through a [Loader], failures are collected by the
+// [Loader] instead, and getters return the zero value of their type. The
+// getters are built on [Get] and [GetSlice], which read values of any type
+// with a [Parser] and constrain them with validators. The design encourages
+// method chaining for concise configuration expressions.
 type Env struct {
-	key   string
-	value string
+	key    string
+	value  string
+	origin Origin
+	secret bool
+	loader *Loader
+
+	separator   string
+	kvSeparator string
 }
 
 // NewEnv creates and returns a new instance of [Env], initializing it with a
 // given environment variable key. It fetches the corresponding value from the
-// system's environment variables, or an empty string if the variable is not
-// present.
+// system's environment variables, falling back to the configuration file
+// selected by [ConfigFileKey], or an empty string if the variable is not
+// present in either. It panics if the configuration file cannot be read.
 func NewEnv(key string) Env {
-	return Env{
-		key:   key,
-		value: os.Getenv(key),
+	var l *Loader
+
+	return l.NewEnv(key)
+}
+
+// newError creates a structured [*errors.Error] for the given sentinel, carrying
+// the key and value of the [Env] instance as context so that callers can
+// inspect them without parsing the error message. The value of a secret [Env]
+// is attached as a sensitive field, so that it is never rendered.
+func (e Env) newError(sentinel error) *errors.Error {
+	err := errors.NewError(sentinel).
+		WithSource(consts.SE).
+		WithField("env", e.key)
+
+	if e.IsSecret() {
+		return err.WithSensitiveField("value", e.value)
 	}
+
+	return err.WithField("value", e.value)
+}
+
+// fail reports a validation failure of the [Env] instance, describing the
+// value that was expected instead. Without a [Loader] it panics with the
+// error; otherwise the failure is recorded by the [Loader] and the caller is
+// expected to carry on with a zero value.
+func (e Env) fail(err *errors.Error, expected string) {
+	err.WithField("expected", expected)
+
+	if e.loader == nil {
+		panic(err)
+	}
+
+	e.loader.report(e, err)
 }
 
 // checkRequired ensures that the environment variable associated with the [Env]
-// instance is present and not empty. It panics if these conditions are not met,
+// instance is present and not empty. It fails if these conditions are not met,
 // indicating a missing required environment variable.
 func (e Env) checkRequired() {
 	if e.value == "" {
-		panic(errors.Wrap(
-			errors.ErrMissingEnv,
-			fmt.Errorf("environment variable %s is not set", e.key),
-		))
+		e.fail(errors.NewError(errors.ErrMissingEnv).
+			WithSource(consts.SE).
+			WithField("env", e.key), "a non-empty value")
 	}
 }
 
@@ -54,7 +89,7 @@ func (e Env) checkRequired() {
 // with the [Env] instance. If the environment variable is not set or its value
 // is empty, an empty string is returned.
 func (e Env) GetString() string {
-	return e.value
+	return Get(e, StringParser)
 }
 
 // GetPort retrieves the port number from the environment variable associated
@@ -62,7 +97,7 @@ func (e Env) GetString() string {
 // range for TCP/UDP ports (1-65535) and if it is not, or if the value is not a
 // valid integer, the method will panic.
 func (e Env) GetPort() int {
-	return e.WithIntInRange(0, 65535).GetInt()
+	return Get(e, IntParser, InRange(0, 65535))
 }
 
 // GetAddress retrieves the string representation of an IP address from the
@@ -72,189 +107,60 @@ func (e Env) GetPort() int {
 // parsing fails or the value is empty, a panic is induced with appropriate
 // error details.
 func (e Env) GetAddress() string {
-	if e.value == "localhost" {
-		return e.value
-	}
-
-	ip := net.ParseIP(e.value)
-
-	if ip == nil {
-		panic(errors.Wrap(
-			errors.ErrInvalidEnv,
-			fmt.Errorf("env: %s", e.key),
-			fmt.Errorf("value: %s", e.value),
-		))
+	if e.value == "" {
+		e.fail(e.newError(errors.ErrInvalidEnv), AddressParser.Expected)
+		return ""
 	}
 
-	return ip.String()
+	return Get(e, AddressParser)
 }
 
 // GetBool interprets the associated environment variable's value as a boolean
 // and returns the result. It returns false if the value is an empty string. If
 // the value cannot be interpreted as a boolean, it panics.
 func (e Env) GetBool() bool {
-	if e.value == "" {
-		return false
-	}
-
-	b, err := strconv.ParseBool(e.value)
-	if err != nil {
-		panic(err)
-	}
-
-	return b
+	return Get(e, BoolParser)
 }
 
 // GetInt retrieves an integer from the environment variable associated with the
 // [Env] instance. If the variable is unset or empty, it defaults to 0.
 // Conversion errors result in a panic.
 func (e Env) GetInt() int {
-	if e.value == "" {
-		return 0
-	}
-
-	i, err := strconv.Atoi(e.value)
-	if err != nil {
-		panic(err)
-	}
-
-	return i
+	return Get(e, IntParser)
 }
 
 func (e Env) GetFloat64() float64 {
-	if e.value == "" {
-		return 0
-	}
-
-	i, err := strconv.ParseFloat(e.value, 64)
-	if err != nil {
-		panic(err)
-	}
-
-	return i
+	return Get(e, Float64Parser)
 }
 
 func (e Env) GetDirectoryOrCreate() string {
-	if e.value == "" {
-		return e.value
-	}
-
-	if !utils.IsDirectory(e.value) {
-		if err := os.MkdirAll(e.value, 0755); err != nil {
-			panic(errors.Wrap(
-				errors.ErrInvalidEnv,
-				errors.ErrInvalidPath,
-				fmt.Errorf("env: %s", e.key),
-				fmt.Errorf("value: %s", e.value),
-				err,
-			))
-		}
-	}
-
-	return e.value
+	return Get(e, DirectoryOrCreateParser)
 }
 
 func (e Env) GetFileOrCreate() string {
-	if e.value == "" {
-		return e.value
-	}
-
-	if !utils.IsFile(e.value) {
-		if err := os.MkdirAll(filepath.Base(e.value), 0755); err != nil {
-			panic(errors.Wrap(
-				errors.ErrInvalidEnv,
-				errors.ErrInvalidPath,
-				fmt.Errorf("env: %s", e.key),
-				fmt.Errorf("value: %s", e.value),
-				err,
-			))
-		} else {
-			if _, err := os.Create(e.value); err != nil {
-				panic(errors.Wrap(
-					errors.ErrInvalidEnv,
-					errors.ErrInvalidPath,
-					fmt.Errorf("env: %s", e.key),
-					fmt.Errorf("value: %s", e.value),
-					err,
-				))
-			}
-		}
-	}
-
-	return e.value
+	return Get(e, FileOrCreateParser)
 }
 
 func (e Env) GetDirectory() string {
-	if e.value == "" {
-		return e.value
-	}
-
-	if !utils.IsDirectory(e.value) {
-		panic(errors.Wrap(
-			errors.ErrInvalidEnv,
-			errors.ErrInvalidPath,
-			fmt.Errorf("env: %s", e.key),
-			fmt.Errorf("value: %s", e.value),
-		))
-	}
-
-	return e.value
+	return Get(e, DirectoryParser)
 }
 
 func (e Env) GetFile() string {
-	if e.value == "" {
-		return e.value
-	}
-
-	if !utils.IsFile(e.value) {
-		panic(errors.Wrap(
-			errors.ErrInvalidEnv,
-			errors.ErrInvalidPath,
-			fmt.Errorf("env: %s", e.key),
-			fmt.Errorf("value: %s", e.value),
-		))
-	}
-
-	return e.value
+	return Get(e, FileParser)
 }
 
 func (e Env) GetDuration() time.Duration {
-	if e.value == "" {
-		return 0
-	}
-
-	d, err := time.ParseDuration(e.value)
-	if err != nil {
-		panic(errors.Wrap(
-			errors.ErrInvalidEnv,
-			fmt.Errorf("env: %s", e.key),
-			fmt.Errorf("value: %s", e.value),
-			err,
-		))
-	}
-
-	return d
+	return Get(e, DurationParser)
 }
 
 // GetURL retrieves the URL from the associated environment variable. It ensures
 // that the value is a valid URL format and returns it as a string. If the
 // environment variable is not set or its value is empty, an empty string is
 // returned. A panic occurs if the value cannot be parsed into a valid URL.
+// Relative references such as "foo" are accepted; use [Env.GetAbsoluteURL] to
+// require a scheme and a host.
 func (e Env) GetURL() string {
-	if e.value == "" {
-		return e.value
-	}
-
-	if _, err := url.Parse(e.value); err != nil {
-		panic(errors.Wrap(
-			errors.ErrInvalidEnv,
-			fmt.Errorf("env: %s", e.key),
-			fmt.Errorf("value: %s", e.value),
-			err,
-		))
-	}
-
-	return e.value
+	return Get(e, URLParser)
 }
 
 // GetURLPath extracts the path component from a URL present in the environment
@@ -262,20 +168,24 @@ func (e Env) GetURL() string {
 // included path. If the environment variable is unset, empty, or contains an
 // invalid URL, it triggers a panic.
 func (e Env) GetURLPath() string {
-	if e.value == "" {
-		return e.value
-	}
+	return Get(e, URLPathParser)
+}
 
-	if _, err := url.Parse(e.value); err != nil {
-		panic(errors.Wrap(
-			errors.ErrInvalidEnv,
-			fmt.Errorf("env: %s", e.key),
-			fmt.Errorf("value: %s", e.value),
-			err,
-		))
-	}
+// WithSecret marks the environment variable as holding a secret, such as a
+// token or a signing key, regardless of its key. The value of a secret [Env] is
+// redacted from every error it produces. It returns the same [Env] instance to
+// enable method chaining.
+func (e Env) WithSecret() Env {
+	e.secret = true
 
-	return e.value
+	return e
+}
+
+// IsSecret reports whether the value of the environment variable must be
+// treated as a secret, either because it was marked with [Env.WithSecret] or
+// because its key looks like it names a secret, see [IsSecretKey].
+func (e Env) IsSecret() bool {
+	return e.secret || IsSecretKey(e.key)
 }
 
 // WithIntInRange ensures that the integer value of the environment variable is
@@ -283,16 +193,12 @@ func (e Env) GetURLPath() string {
 // panics to signal an invalid configuration. It returns the same [Env] instance
 // to enable method chaining.
 func (e Env) WithIntInRange(min, max int) Env {
-	v := e.GetInt()
-
-	if v < min || v > max {
-		panic(errors.Wrap(
-			errors.ErrInvalidEnv,
-			fmt.Errorf("env: %s", e.key),
-			fmt.Errorf("value: %d", v),
-			fmt.Errorf("min: %d", min),
-			fmt.Errorf("max %d", max),
-		))
+	r := InRange(min, max)
+
+	if v := e.GetInt(); !r.Check(v) {
+		e.fail(e.newError(errors.ErrInvalidEnv).
+			WithField("min", min).
+			WithField("max", max), expected(IntParser.Expected, []Validator[int]{r}))
 	}
 
 	return e
@@ -303,13 +209,20 @@ func (e Env) WithIntInRange(min, max int) Env {
 // environment variable is absent or empty. The method returns the [Env]
 // instance with the updated value, supporting further method chaining.
 func (e Env) WithDefault(value string) Env {
-	if e.value == "" {
+	if e.value == "" && value != "" {
 		e.value = value
+		e.origin = OriginDefault
 	}
 
 	return e
 }
 
+// Origin returns the layer the value of the environment variable was read
+// from, or [OriginNone] if it is not set.
+func (e Env) Origin() Origin {
+	return e.origin
+}
+
 // WithRequiredIf enforces a conditional requirement on the environment
 // variable's value associated with the [Env] instance, based on the value of
 // another specified environment variable. If the specified environment
@@ -319,7 +232,7 @@ func (e Env) WithDefault(value string) Env {
 // method chaining by returning the same [Env] instance for further
 // configuration.
 func (e Env) WithRequiredIf(key string, values []string) Env {
-	ne := NewEnv(key)
+	ne := e.loader.NewEnv(key)
 
 	if utils.StringInSlice(ne.GetString(), values) {
 		e.checkRequired()
@@ -339,21 +252,35 @@ func (e Env) WithRequired() Env {
 
 // WithOptions validates the environment variable's value against a set of
 // predefined acceptable strings. If the value is not within these options and
-// is not empty, it raises an error to signal an invalid value. This method
-// facilitates fluent configuration by returning the same [Env] instance for
-// potential additional configurations.
+// is not empty, it raises an error to signal an invalid value. For a list
+// declared with [Env.WithSeparator], each element is validated instead, and
+// for a map the keys are. This method facilitates fluent configuration by
+// returning the same [Env] instance for potential additional configurations.
 func (e Env) WithOptions(opts ...string) Env {
 	if e.value == "" {
 		return e
 	}
 
-	if !utils.StringInSlice(e.value, opts) {
-		panic(errors.Wrap(
-			errors.ErrInvalidEnv,
-			fmt.Errorf("env: %s", e.key),
-			fmt.Errorf("value: %s", e.value),
-			fmt.Errorf("options: %v", opts),
-		))
+	if e.separator == "" {
+		if !utils.StringInSlice(e.value, opts) {
+			e.fail(e.newError(errors.ErrInvalidEnv).
+				WithField("options", opts), fmt.Sprintf("one of %v", opts))
+		}
+
+		return e
+	}
+
+	for _, elem := range e.elements() {
+		if e.kvSeparator != "" {
+			elem, _, _ = strings.Cut(elem, e.kvSeparator)
+			elem = strings.TrimSpace(elem)
+		}
+
+		if !utils.StringInSlice(elem, opts) {
+			e.fail(e.newElementError(elem).
+				WithField("options", opts), fmt.Sprintf("elements among %v separated by %q", opts, e.separator))
+			break
+		}
 	}
 
 	return e
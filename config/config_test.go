@@ -0,0 +1,118 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stellarentropy/gravity-assist-common/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// recoverError runs fn and returns the [error] it panicked with, or nil if it
+// did not panic.
+func recoverError(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = r.(error)
+		}
+	}()
+
+	fn()
+
+	return nil
+}
+
+// TestIsSecretKey verifies the detection of environment variable keys naming
+// secrets.
+func TestIsSecretKey(t *testing.T) {
+	assert.True(t, IsSecretKey("SE_GA_JWT_SIGNING_KEY"))
+	assert.True(t, IsSecretKey("SE_GA_PCM_TOKEN"))
+	assert.True(t, IsSecretKey("se_ga_hmac_secret"))
+	assert.False(t, IsSecretKey("SE_GA_LOG_FORMAT"))
+	assert.False(t, IsSecretKey("SE_GA_MONKEY_COUNT"))
+}
+
+// TestEnvRedactsSecrets verifies that the value of a secret environment
+// variable, whether detected from its key or marked explicitly, never reaches
+// the error message, its JSON representation or a log line.
+func TestEnvRedactsSecrets(t *testing.T) {
+	secret := "s3cr3t-9f8e7d"
+
+	t.Setenv("SE_GA_TEST_SIGNING_KEY", secret)
+	t.Setenv("SE_GA_TEST_UPSTREAM", secret)
+
+	for _, fn := range []func(){
+		func() { NewEnv("SE_GA_TEST_SIGNING_KEY").GetInt() },
+		func() { NewEnv("SE_GA_TEST_SIGNING_KEY").WithOptions("a", "b") },
+		func() { NewEnv("SE_GA_TEST_UPSTREAM").WithSecret().GetDuration() },
+		func() { NewEnv("SE_GA_TEST_UPSTREAM").WithSecret().GetBool() },
+	} {
+		err := recoverError(fn)
+		assert.True(t, errors.Is(err, errors.ErrInvalidEnv))
+
+		b, jerr := json.Marshal(err)
+		assert.NoError(t, jerr)
+
+		var buf bytes.Buffer
+		logger := zerolog.New(&buf)
+		logger.Error().Err(err).Msg("invalid configuration")
+
+		for _, out := range []string{err.Error(), fmt.Sprintf("%+v", err), string(b), buf.String()} {
+			assert.NotContains(t, out, secret)
+		}
+	}
+
+	err := recoverError(func() { NewEnv("SE_GA_TEST_UPSTREAM").GetInt() })
+	assert.Contains(t, err.Error(), secret)
+}
+
+// TestEnvSecretFile verifies that a value is read from the file named by the
+// _FILE variant of its key, trimmed, treated as a secret and never reported in
+// errors.
+func TestEnvSecretFile(t *testing.T) {
+	secret := "s3cr3t-9f8e7d"
+	path := filepath.Join(t.TempDir(), "hmac")
+	assert.NoError(t, os.WriteFile(path, []byte(secret+"\n"), 0400))
+
+	t.Setenv("SE_GA_TEST_UPSTREAM_FILE", path)
+
+	e := NewEnv("SE_GA_TEST_UPSTREAM")
+	assert.Equal(t, secret, e.GetString())
+	assert.Equal(t, OriginSecretFile, e.Origin())
+	assert.True(t, e.IsSecret())
+
+	err := recoverError(func() { e.GetInt() })
+	assert.True(t, errors.Is(err, errors.ErrInvalidEnv))
+	assert.NotContains(t, err.Error(), secret)
+
+	t.Setenv("SE_GA_TEST_UPSTREAM", "from-env")
+	assert.Equal(t, "from-env", NewEnv("SE_GA_TEST_UPSTREAM").GetString())
+}
+
+// TestEnvSecretFileInvalid verifies that missing, oversized or group-writable
+// secret files are rejected and reported against the _FILE variable.
+func TestEnvSecretFileInvalid(t *testing.T) {
+	dir := t.TempDir()
+
+	writable := filepath.Join(dir, "writable")
+	assert.NoError(t, os.WriteFile(writable, []byte("secret"), 0600))
+	assert.NoError(t, os.Chmod(writable, 0620))
+
+	large := filepath.Join(dir, "large")
+	assert.NoError(t, os.WriteFile(large, bytes.Repeat([]byte("a"), secretFileMaxSize+1), 0400))
+
+	for _, path := range []string{writable, large, filepath.Join(dir, "missing"), dir} {
+		t.Setenv("SE_GA_TEST_UPSTREAM_FILE", path)
+
+		l := NewLoader()
+		l.NewEnv("SE_GA_TEST_UPSTREAM")
+
+		assert.True(t, errors.Is(l.Err(), errors.ErrInvalidSecretFile), path)
+		assert.Equal(t, "SE_GA_TEST_UPSTREAM_FILE", l.Failures()[0].Key)
+	}
+}
@@ -0,0 +1,266 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/stellarentropy/gravity-assist-common/utils"
+)
+
+// Definition documents a variable bound to a struct field by [Loader.Bind],
+// as described by the tags of the field.
+type Definition struct {
+	Group           string             `json:"group"`
+	Key             string             `json:"key"`
+	Type            string             `json:"type"`
+	Kind            string             `json:"kind,omitempty"`
+	Default         *string            `json:"default,omitempty"`
+	ProfileDefaults map[Profile]string `json:"profileDefaults,omitempty"`
+	Options         []string           `json:"options,omitempty"`
+	Min             *float64           `json:"min,omitempty"`
+	Max             *float64           `json:"max,omitempty"`
+	Required        bool               `json:"required"`
+	RequiredIf      string             `json:"requiredIf,omitempty"`
+	Aliases         []string           `json:"aliases,omitempty"`
+	Secret          bool               `json:"secret"`
+	Description     string             `json:"description,omitempty"`
+}
+
+// definitions holds the [Definition]s registered with [Register], by group.
+var definitions = struct {
+	sync.Mutex
+	groups map[string][]Definition
+}{groups: map[string][]Definition{}}
+
+// Register records the [Definition]s of the struct pointed to by v under the
+// given group, so that they are documented along with those of every other
+// registered struct by [Registered]. Registering a group again replaces its
+// definitions. It is meant to be called from the init function of the
+// package declaring the struct.
+func Register(group string, v any) {
+	defs := Describe(group, v)
+
+	definitions.Lock()
+	defer definitions.Unlock()
+
+	definitions.groups[group] = defs
+}
+
+// Registered returns the [Definition]s of every struct registered with
+// [Register], ordered by group and then by field.
+func Registered() []Definition {
+	definitions.Lock()
+	defer definitions.Unlock()
+
+	groups := make([]string, 0, len(definitions.groups))
+	for g := range definitions.groups {
+		groups = append(groups, g)
+	}
+
+	sort.Strings(groups)
+
+	var defs []Definition
+	for _, g := range groups {
+		defs = append(defs, definitions.groups[g]...)
+	}
+
+	return defs
+}
+
+// Describe returns the [Definition] of every variable bound to a field of the
+// struct pointed to by v, in the order of the fields, under the given group.
+// It panics with [errors.ErrInvalidBinding] if v is not a non-nil pointer to
+// a struct or if a tag cannot be parsed.
+func Describe(group string, v any) []Definition {
+	var defs []Definition
+
+	walkFields(structValue(v), "", func(fv reflect.Value, f reflect.StructField, key string) {
+		def := Definition{
+			Group:       group,
+			Key:         key,
+			Type:        f.Type.String(),
+			Kind:        utils.GetStructTag(f, TagKind),
+			Required:    tagBool(f, TagRequired),
+			Secret:      tagBool(f, TagSecret) || IsSecretKey(key),
+			Description: utils.GetStructTag(f, TagDescription),
+		}
+
+		if d, ok := f.Tag.Lookup(TagDefault); ok {
+			def.Default = &d
+		}
+
+		def.ProfileDefaults = tagProfileDefaults(f)
+
+		if opts := utils.GetStructTag(f, TagOptions); opts != "" {
+			def.Options = strings.Split(opts, ",")
+		}
+
+		if f.Type.Kind() == reflect.Float64 {
+			if min, max, ok := tagFloatRange(f); ok {
+				def.Min, def.Max = &min, &max
+			}
+		} else if min, max, ok := tagRange(f); ok {
+			fmin, fmax := float64(min), float64(max)
+			def.Min, def.Max = &fmin, &fmax
+		} else if def.Kind == KindPort {
+			min, max := 0.0, 65535.0
+			def.Min, def.Max = &min, &max
+		}
+
+		if other, values, ok := tagRequiredIf(f); ok {
+			def.RequiredIf = other + "=" + strings.Join(values, "|")
+		}
+
+		for _, a := range tagAliases(f) {
+			def.Aliases = append(def.Aliases, a.String())
+		}
+
+		defs = append(defs, def)
+	})
+
+	return defs
+}
+
+// RenderJSON renders the given [Definition]s as an indented JSON array.
+func RenderJSON(defs []Definition) ([]byte, error) {
+	b, err := json.MarshalIndent(defs, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
+
+// RenderMarkdown renders the given [Definition]s as a Markdown reference, with
+// one section per group holding a table of its variables.
+func RenderMarkdown(defs []Definition) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("# Configuration reference\n\n")
+	buf.WriteString("<!-- Code generated by go generate; DO NOT EDIT. -->\n")
+
+	group := ""
+
+	for i, d := range defs {
+		if i == 0 || d.Group != group {
+			group = d.Group
+
+			fmt.Fprintf(&buf, "\n## %s\n\n", group)
+			buf.WriteString("| Variable | Type | Default | Options | Range | Required | Description |\n")
+			buf.WriteString("|---|---|---|---|---|---|---|\n")
+		}
+
+		fmt.Fprintf(&buf, "| `%s` | %s | %s | %s | %s | %s | %s |\n",
+			d.Key,
+			markdownType(d),
+			markdownDefault(d),
+			markdownOptions(d.Options),
+			markdownRange(d.Min, d.Max),
+			markdownRequired(d),
+			markdownDescription(d))
+	}
+
+	return buf.Bytes()
+}
+
+// markdownType renders the type of a [Definition], with its kind if any, and
+// whether it holds a secret.
+func markdownType(d Definition) string {
+	t := d.Type
+	if d.Kind != "" {
+		t += " (" + d.Kind + ")"
+	}
+
+	if d.Secret {
+		t += ", secret"
+	}
+
+	return t
+}
+
+// markdownCode renders an optional value as inline code, or an empty cell.
+func markdownCode(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return "`" + markdownEscape(*s) + "`"
+}
+
+// markdownDefault renders the default of a [Definition], followed by the
+// defaults of the profiles that change it.
+func markdownDefault(d Definition) string {
+	def := markdownCode(d.Default)
+
+	for _, p := range Profiles {
+		if pd, ok := d.ProfileDefaults[p]; ok {
+			def += fmt.Sprintf("<br>%s: %s", p, markdownCode(&pd))
+		}
+	}
+
+	return strings.TrimPrefix(def, "<br>")
+}
+
+// markdownOptions renders a list of options as inline code.
+func markdownOptions(opts []string) string {
+	quoted := make([]string, 0, len(opts))
+	for _, o := range opts {
+		quoted = append(quoted, "`"+markdownEscape(o)+"`")
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+// markdownRange renders an inclusive range, or an empty cell.
+func markdownRange(min *float64, max *float64) string {
+	if min == nil || max == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(*min, 'f', -1, 64) + "–" + strconv.FormatFloat(*max, 'f', -1, 64)
+}
+
+// markdownRequired renders whether a variable is required, and when.
+func markdownRequired(d Definition) string {
+	switch {
+	case d.Required:
+		return "yes"
+	case d.RequiredIf != "":
+		return "if `" + markdownEscape(d.RequiredIf) + "`"
+	}
+
+	return "no"
+}
+
+// markdownDescription renders the description of a [Definition], followed by
+// its deprecated aliases if any.
+func markdownDescription(d Definition) string {
+	desc := markdownEscape(d.Description)
+
+	if len(d.Aliases) > 0 {
+		aliases := make([]string, 0, len(d.Aliases))
+		for _, a := range d.Aliases {
+			alias := ParseAlias(a)
+			if alias.Since != "" {
+				aliases = append(aliases, fmt.Sprintf("`%s` (since %s)", alias.Key, markdownEscape(alias.Since)))
+			} else {
+				aliases = append(aliases, "`"+alias.Key+"`")
+			}
+		}
+
+		desc = strings.TrimSpace(desc + " Deprecated aliases: " + strings.Join(aliases, ", ") + ".")
+	}
+
+	return desc
+}
+
+// markdownEscape escapes the characters that would break a table cell.
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// describeConfig exercises the tags documented by [Describe].
+type describeConfig struct {
+	Sampler string  `env:"SE_GA_TEST_SAMPLER" default:"always" options:"always,traceIdRatio" required:"true" description:"Trace sampler."`
+	Ratio   float64 `env:"SE_GA_TEST_RATIO" min:"0.05" max:"0.5" requiredif:"SE_GA_TEST_SAMPLER=traceIdRatio" description:"Sampled fraction | ratio."`
+	Workers int     `env:"SE_GA_TEST_WORKERS" min:"1" max:"64"`
+	Token   string  `env:"SE_GA_TEST_TOKEN"`
+
+	Public listenerConfig `prefix:"SE_GA_TEST_PUBLIC_"`
+}
+
+// TestDescribe verifies that [Describe] documents every bound variable from
+// the tags of its field.
+func TestDescribe(t *testing.T) {
+	defs := Describe("test", &describeConfig{})
+	assert.Len(t, defs, 7)
+
+	always, min, max, ports := "always", 1.0, 64.0, 65535.0
+	zero, ratioMin, ratioMax := 0.0, 0.05, 0.5
+
+	assert.Equal(t, Definition{
+		Group: "test", Key: "SE_GA_TEST_SAMPLER", Type: "string", Default: &always,
+		Options: []string{"always", "traceIdRatio"}, Required: true, Description: "Trace sampler.",
+	}, defs[0])
+	assert.Equal(t, "SE_GA_TEST_SAMPLER=traceIdRatio", defs[1].RequiredIf)
+	assert.Equal(t, &ratioMin, defs[1].Min)
+	assert.Equal(t, &ratioMax, defs[1].Max)
+	assert.Equal(t, &min, defs[2].Min)
+	assert.Equal(t, &max, defs[2].Max)
+	assert.True(t, defs[3].Secret)
+	assert.Equal(t, "SE_GA_TEST_PUBLIC_LISTEN_PORT", defs[5].Key)
+	assert.Equal(t, &zero, defs[5].Min)
+	assert.Equal(t, &ports, defs[5].Max)
+
+	md := string(RenderMarkdown(defs))
+	assert.Contains(t, md, "## test")
+	assert.Contains(t, md, "| `SE_GA_TEST_RATIO` | float64 |  |  | 0.05–0.5 | if `SE_GA_TEST_SAMPLER=traceIdRatio` | Sampled fraction \\| ratio. |")
+	assert.Contains(t, md, "| `SE_GA_TEST_TOKEN` | string, secret |")
+	assert.Contains(t, md, "| `SE_GA_TEST_WORKERS` | int |  |  | 1–64 |")
+}
+
+// TestBindRequiredIf verifies that a variable tagged with [TagRequiredIf] is
+// only required when the other variable holds one of the given values.
+func TestBindRequiredIf(t *testing.T) {
+	var c describeConfig
+
+	l := NewMapLoader(map[string]string{"SE_GA_TEST_SAMPLER": "traceIdRatio", "SE_GA_TEST_WORKERS": "2"})
+	l.Bind(&c)
+	assert.Len(t, l.Failures(), 1)
+	assert.Equal(t, "SE_GA_TEST_RATIO", l.Failures()[0].Key)
+
+	l = NewMapLoader(map[string]string{"SE_GA_TEST_WORKERS": "2"})
+	l.Bind(&c)
+	assert.NoError(t, l.Err())
+}
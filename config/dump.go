@@ -0,0 +1,77 @@
+package config
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"time"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+)
+
+// Origins maps the key of every variable bound by [Loader.Bind] to the layer
+// its value was read from.
+type Origins map[string]Origin
+
+// DumpEntry describes the effective value of a single field of a struct
+// bound with [Loader.Bind], as rendered by [Dump].
+type DumpEntry struct {
+	Key    string `json:"key"`
+	Field  string `json:"field"`
+	Value  any    `json:"value"`
+	Source Origin `json:"source"`
+	Secret bool   `json:"secret,omitempty"`
+}
+
+// DumpEntries describes the effective value of every field of the struct
+// pointed to by v that is bound to a variable, in the order of the fields,
+// with the layer each value came from taken from origins. The value of secret
+// fields, marked with [TagSecret], named like a secret according to
+// [IsSecretKey] or read from a secret file, is replaced with
+// [errors.Redacted]. Durations are rendered as strings such as "1m30s", and
+// URLs as strings with their password redacted.
+//
+// DumpEntries panics with [errors.ErrInvalidBinding] if v is not a non-nil
+// pointer to a struct.
+func DumpEntries(v any, origins Origins) []DumpEntry {
+	var entries []DumpEntry
+
+	walkFields(structValue(v), "", func(fv reflect.Value, f reflect.StructField, key string) {
+		entry := DumpEntry{
+			Key:    key,
+			Field:  f.Name,
+			Source: origins[key],
+			Secret: tagBool(f, TagSecret) || IsSecretKey(key) || origins[key] == OriginSecretFile,
+		}
+
+		switch {
+		case entry.Secret:
+			entry.Value = errors.Redacted
+		case fv.Type() == durationType:
+			entry.Value = time.Duration(fv.Int()).String()
+		case fv.Type() == urlType:
+			if u := fv.Interface().(*url.URL); u != nil {
+				entry.Value = u.Redacted()
+			}
+		case fv.Type() == durationSliceType:
+			var durations []string
+			for _, d := range fv.Interface().([]time.Duration) {
+				durations = append(durations, d.String())
+			}
+			entry.Value = durations
+		default:
+			entry.Value = fv.Interface()
+		}
+
+		entries = append(entries, entry)
+	})
+
+	return entries
+}
+
+// Dump renders the [DumpEntries] of the struct pointed to by v as a JSON
+// array, so that the configuration a service runs with can be logged or
+// served without leaking its secrets.
+func Dump(v any, origins Origins) ([]byte, error) {
+	return json.Marshal(DumpEntries(v, origins))
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+)
+
+// Parser converts the raw value of an environment variable into a value of
+// type T, for use with [Get] and [GetSlice]. The parsers of the getters of
+// [Env], such as [IntParser] or [DurationParser], are provided, and new ones
+// can be declared for types of the application.
+type Parser[T any] struct {
+	// Expected describes a single accepted value, such as "an integer", and
+	// is reported when the value cannot be parsed.
+	Expected string
+
+	// Elements describes a list of accepted values, such as "integers", and
+	// is reported when an element of a list cannot be parsed. When empty, it
+	// is derived from Expected.
+	Elements string
+
+	// Parse converts a non-empty value, returning an error describing why it
+	// is not valid if it cannot.
+	Parse func(value string) (T, error)
+}
+
+// elements describes a list of values accepted by the [Parser].
+func (p Parser[T]) elements() string {
+	if p.Elements != "" {
+		return p.Elements
+	}
+
+	return "elements each " + p.Expected
+}
+
+// Validator constrains the values of type T accepted by [Get] and [GetSlice]
+// once parsed. The [InRange], [Matches], [OneOf], [NonEmpty] and [Predicate]
+// validators are provided.
+type Validator[T any] struct {
+	// Expected qualifies the description of the [Parser], such as "between 0
+	// and 1" for "a floating-point number between 0 and 1", and is reported
+	// when a value is rejected.
+	Expected string
+
+	// Check reports whether the value is accepted.
+	Check func(value T) bool
+}
+
+// valid reports whether v is accepted by every validator.
+func valid[T any](v T, validators []Validator[T]) bool {
+	for _, validator := range validators {
+		if !validator.Check(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// expected qualifies the description of the values accepted by a [Parser]
+// with the descriptions of the validators.
+func expected[T any](description string, validators []Validator[T]) string {
+	for _, v := range validators {
+		description += " " + v.Expected
+	}
+
+	return description
+}
+
+// Get parses the value of the environment variable associated with e with p,
+// and checks the result against every validator in order. It returns the zero
+// value of T if the variable is unset or empty, without running the
+// validators; use [Env.WithRequired] to reject such values. If the value
+// cannot be parsed or is rejected by a validator, it panics, or the failure is
+// recorded by the [Loader] of e and the zero value of T is returned.
+//
+// For example, a ratio can be read with:
+//
+//	ratio := config.Get(config.NewEnv("SE_GA_TRACE_ID_RATIO"), config.Float64Parser, config.InRange(0.0, 1.0))
+func Get[T any](e Env, p Parser[T], validators ...Validator[T]) T {
+	var zero T
+
+	if e.value == "" {
+		return zero
+	}
+
+	v, err := p.Parse(e.value)
+	if err != nil {
+		e.fail(e.newError(errors.ErrInvalidEnv).
+			WithCause(err), expected(p.Expected, validators))
+		return zero
+	}
+
+	if !valid(v, validators) {
+		e.fail(e.newError(errors.ErrInvalidEnv), expected(p.Expected, validators))
+		return zero
+	}
+
+	return v
+}
+
+// GetSlice splits the value of the environment variable associated with e
+// into a list, as [Env.GetStringSlice] does, and parses each element with p,
+// checking it against every validator in order. It returns nil if the
+// variable is unset or empty. If an element cannot be parsed or is rejected by
+// a validator, it fails as [Get] does, reporting the element.
+func GetSlice[T any](e Env, p Parser[T], validators ...Validator[T]) []T {
+	e = e.asList()
+
+	var values []T
+
+	for _, elem := range e.elements() {
+		v, err := p.Parse(elem)
+		if err != nil || !valid(v, validators) {
+			ferr := e.newElementError(elem)
+			if err != nil {
+				ferr = ferr.WithCause(err)
+			}
+
+			e.fail(ferr, fmt.Sprintf("%s separated by %q", expected(p.elements(), validators), e.separator))
+			return nil
+		}
+
+		values = append(values, v)
+	}
+
+	return values
+}
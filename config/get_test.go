@@ -0,0 +1,84 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGet verifies that [Get] and [GetSlice] parse values with a [Parser] and
+// check them against validators, skipping unset variables.
+func TestGet(t *testing.T) {
+	t.Setenv("SE_GA_TEST_RATIO", "0.25")
+	t.Setenv("SE_GA_TEST_TIMEOUT", "30s")
+	t.Setenv("SE_GA_TEST_BUCKET", "reports-eu")
+	t.Setenv("SE_GA_TEST_REGIONS", "eu, us")
+
+	assert.Equal(t, 0.25, Get(NewEnv("SE_GA_TEST_RATIO"), Float64Parser, InRange(0.0, 1.0)))
+	assert.Equal(t, 30*time.Second, Get(NewEnv("SE_GA_TEST_TIMEOUT"), DurationParser, InRange(time.Second, time.Minute)))
+	assert.Equal(t, "reports-eu", Get(NewEnv("SE_GA_TEST_BUCKET"), StringParser, NonEmpty(), Matches(`^[a-z0-9-]+$`)))
+	assert.Equal(t, []string{"eu", "us"}, GetSlice(NewEnv("SE_GA_TEST_REGIONS"), StringParser, OneOf("eu", "us", "ap")))
+
+	upper := Parser[string]{
+		Expected: "a string",
+		Parse: func(s string) (string, error) {
+			return strings.ToUpper(s), nil
+		},
+	}
+	assert.Equal(t, "REPORTS-EU", Get(NewEnv("SE_GA_TEST_BUCKET"), upper))
+
+	assert.Zero(t, Get(NewEnv("SE_GA_TEST_UNSET"), Float64Parser, InRange(0.5, 1.0)))
+	assert.Nil(t, GetSlice(NewEnv("SE_GA_TEST_UNSET"), IntParser))
+}
+
+// TestGetInvalid verifies that values rejected by the [Parser] or by a
+// validator fail with [errors.ErrInvalidEnv], describing the expected value.
+func TestGetInvalid(t *testing.T) {
+	t.Setenv("SE_GA_TEST_RATIO", "1.5")
+	t.Setenv("SE_GA_TEST_TIMEOUT", "2m")
+	t.Setenv("SE_GA_TEST_BUCKET", "Reports_EU")
+	t.Setenv("SE_GA_TEST_BLANK", "   ")
+	t.Setenv("SE_GA_TEST_WORKERS", "-2")
+	t.Setenv("SE_GA_TEST_REGIONS", "eu,mars")
+
+	positive := Predicate("that is positive", func(i int) bool { return i > 0 })
+
+	for _, fn := range []func(){
+		func() { Get(NewEnv("SE_GA_TEST_RATIO"), Float64Parser, InRange(0.0, 1.0)) },
+		func() { Get(NewEnv("SE_GA_TEST_TIMEOUT"), DurationParser, InRange(time.Second, time.Minute)) },
+		func() { Get(NewEnv("SE_GA_TEST_BUCKET"), StringParser, Matches(`^[a-z0-9-]+$`)) },
+		func() { Get(NewEnv("SE_GA_TEST_BLANK"), StringParser, NonEmpty()) },
+		func() { Get(NewEnv("SE_GA_TEST_WORKERS"), IntParser, positive) },
+		func() { GetSlice(NewEnv("SE_GA_TEST_REGIONS"), StringParser, OneOf("eu", "us")) },
+		func() { Get(NewEnv("SE_GA_TEST_RATIO"), IntParser) },
+	} {
+		err := recoverError(fn)
+		assert.True(t, errors.Is(err, errors.ErrInvalidEnv))
+	}
+
+	l := NewLoader()
+	Get(l.NewEnv("SE_GA_TEST_RATIO"), Float64Parser, InRange(0.0, 1.0))
+	GetSlice(l.NewEnv("SE_GA_TEST_REGIONS"), StringParser, OneOf("eu", "us"))
+
+	assert.Equal(t, "a floating-point number between 0 and 1", l.Failures()[0].Expected)
+	assert.Equal(t, `strings among [eu us] separated by ","`, l.Failures()[1].Expected)
+}
+
+// TestGetAddressEmpty verifies that [Env.GetAddress], unlike [Get], rejects
+// an unset or empty variable with [errors.ErrInvalidEnv].
+func TestGetAddressEmpty(t *testing.T) {
+	t.Setenv("SE_GA_TEST_ADDRESS", "")
+
+	err := recoverError(func() { NewEnv("SE_GA_TEST_ADDRESS").GetAddress() })
+	assert.True(t, errors.Is(err, errors.ErrInvalidEnv))
+
+	err = recoverError(func() { NewEnv("SE_GA_TEST_UNSET").GetAddress() })
+	assert.True(t, errors.Is(err, errors.ErrInvalidEnv))
+
+	l := NewLoader()
+	assert.Empty(t, l.NewEnv("SE_GA_TEST_ADDRESS").GetAddress())
+	assert.Equal(t, "an IP address or localhost", l.Failures()[0].Expected)
+}
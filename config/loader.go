@@ -0,0 +1,193 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+)
+
+// Loader collects the validation failures of every [Env] created through it,
+// instead of panicking on the first one. It allows a whole configuration to be
+// read in one pass, so that every problem can be reported at once with
+// [Loader.Err]. A Loader is not safe for concurrent use.
+type Loader struct {
+	values   map[string]string
+	origins  Origins
+	failures []Failure
+
+	deprecations []Deprecation
+	profile      Profile
+}
+
+// NewLoader creates an empty [Loader] reading values from the environment
+// variables of the process and the configuration file selected by
+// [ConfigFileKey].
+func NewLoader() *Loader {
+	return &Loader{}
+}
+
+// NewMapLoader creates an empty [Loader] reading values from the given map
+// only, in place of the environment variables and the configuration file. It
+// allows tests to build a configuration without touching the environment of
+// the process. Values are reported with [OriginMap].
+func NewMapLoader(values map[string]string) *Loader {
+	if values == nil {
+		values = map[string]string{}
+	}
+
+	return &Loader{values: values}
+}
+
+// NewEnv creates a new [Env] for the given key, like the package-level
+// [NewEnv], whose failures are recorded by the [Loader]. Called on a nil
+// [*Loader], it returns an [Env] that panics on failures.
+func (l *Loader) NewEnv(key string) Env {
+	var values map[string]string
+	if l != nil {
+		values = l.values
+	}
+
+	value, origin, err := lookup(values, key)
+
+	if err != nil {
+		// The failure belongs to the variable naming the file that could not
+		// be used, rather than to the key being looked up.
+		fk, _ := errors.Lookup[string](err, "env")
+		fv, _ := errors.Lookup[string](err, "value")
+		expected, _ := errors.Lookup[string](err, "expected")
+
+		Env{key: fk, value: fv, loader: l}.fail(err, expected)
+	}
+
+	return Env{
+		key:    key,
+		value:  value,
+		origin: origin,
+		secret: origin == OriginSecretFile,
+		loader: l,
+	}
+}
+
+// report records a failure of the given [Env]. Only the first failure of each
+// key is kept, as later ones usually derive from it.
+func (l *Loader) report(e Env, err *errors.Error) {
+	for _, f := range l.failures {
+		if f.Key == e.key {
+			return
+		}
+	}
+
+	problem := "not set"
+
+	if !errors.Is(err, errors.ErrMissingEnv) {
+		if e.IsSecret() {
+			problem = "invalid value " + errors.Redacted
+		} else {
+			problem = fmt.Sprintf("invalid value %q", e.value)
+		}
+	}
+
+	expected, _ := errors.Lookup[string](err, "expected")
+
+	l.failures = append(l.failures, Failure{
+		Key:      e.key,
+		Origin:   e.origin,
+		Problem:  problem,
+		Expected: expected,
+		Err:      err,
+	})
+}
+
+// recordOrigin records the layer the value of the given key was read from,
+// for the [Origins] of the [Loader]. Nothing is recorded by a nil [*Loader].
+func (l *Loader) recordOrigin(key string, origin Origin) {
+	if l == nil {
+		return
+	}
+
+	if l.origins == nil {
+		l.origins = Origins{}
+	}
+
+	l.origins[key] = origin
+}
+
+// Origins returns the layer each value bound by [Loader.Bind] was read from,
+// keyed by variable. It returns nil for a nil [*Loader], which records
+// nothing.
+func (l *Loader) Origins() Origins {
+	if l == nil {
+		return nil
+	}
+
+	return l.origins
+}
+
+// Failures returns the failures recorded so far, in the order in which they
+// occurred.
+func (l *Loader) Failures() []Failure {
+	return l.failures
+}
+
+// Err returns a [*LoadError] listing every failure recorded by the [Loader],
+// or nil if the configuration was read without problems.
+func (l *Loader) Err() error {
+	if len(l.failures) == 0 {
+		return nil
+	}
+
+	return &LoadError{Failures: l.failures}
+}
+
+// Failure describes a single environment variable that failed validation: its
+// key, the layer its value came from, what is wrong with the value and what
+// was expected instead. Secret values are never included in the description.
+type Failure struct {
+	Key      string
+	Origin   Origin
+	Problem  string
+	Expected string
+	Err      *errors.Error
+}
+
+// LoadError aggregates every [Failure] found while loading a configuration
+// through a [Loader]. It matches the sentinel of each failure, such as
+// [errors.ErrMissingEnv] or [errors.ErrInvalidEnv], through [errors.Is].
+type LoadError struct {
+	Failures []Failure
+}
+
+// Error renders a summary line followed by the table returned by
+// [LoadError.Table].
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("invalid configuration: %d problem(s)\n%s", len(e.Failures), e.Table())
+}
+
+// Table renders the failures as a table with the key, problem and expected
+// value columns, one failure per line.
+func (e *LoadError) Table() string {
+	var sb strings.Builder
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, "KEY\tPROBLEM\tEXPECTED")
+	for _, f := range e.Failures {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", f.Key, f.Problem, f.Expected)
+	}
+
+	_ = w.Flush()
+
+	return sb.String()
+}
+
+// Unwrap exposes the error of every failure to [errors.Is] and [errors.As].
+func (e *LoadError) Unwrap() []error {
+	errl := make([]error, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		errl = append(errl, f.Err)
+	}
+
+	return errl
+}
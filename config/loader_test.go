@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLoaderCollectsFailures verifies that a [Loader] records every invalid or
+// missing variable instead of panicking, and reports them in one table.
+func TestLoaderCollectsFailures(t *testing.T) {
+	t.Setenv("SE_GA_TEST_PORT", "70000")
+	t.Setenv("SE_GA_TEST_TIMEOUT", "5 minutes")
+	t.Setenv("SE_GA_TEST_FORMAT", "yaml")
+	t.Setenv("SE_GA_TEST_API_TOKEN", "s3cr3t-9f8e7d")
+
+	l := NewLoader()
+
+	l.NewEnv("SE_GA_TEST_PORT").GetPort()
+	assert.Zero(t, l.NewEnv("SE_GA_TEST_TIMEOUT").GetDuration())
+	l.NewEnv("SE_GA_TEST_FORMAT").WithOptions("text", "json")
+	l.NewEnv("SE_GA_TEST_MISSING").WithRequired()
+	l.NewEnv("SE_GA_TEST_API_TOKEN").GetBool()
+	assert.Equal(t, "localhost", l.NewEnv("SE_GA_TEST_UNSET").WithDefault("localhost").GetAddress())
+
+	err := l.Err()
+
+	var le *LoadError
+	assert.True(t, errors.As(err, &le))
+	assert.Len(t, le.Failures, 5)
+	assert.True(t, errors.Is(err, errors.ErrInvalidEnv))
+	assert.True(t, errors.Is(err, errors.ErrMissingEnv))
+
+	table := le.Table()
+	assert.Contains(t, table, "KEY")
+	assert.Contains(t, table, `invalid value "70000"`)
+	assert.Contains(t, table, "an integer between 0 and 65535")
+	assert.Contains(t, table, "a duration such as 30s or 5m")
+	assert.Contains(t, table, "one of [text json]")
+	assert.Contains(t, table, "not set")
+	assert.Contains(t, table, "invalid value "+errors.Redacted)
+	assert.NotContains(t, err.Error(), "s3cr3t-9f8e7d")
+}
+
+// TestLoaderReportsFirstFailurePerKey verifies that only the first failure of
+// a key is recorded, and that a [Loader] without failures reports no error.
+func TestLoaderReportsFirstFailurePerKey(t *testing.T) {
+	t.Setenv("SE_GA_TEST_PORT", "http")
+
+	l := NewLoader()
+	assert.NoError(t, l.Err())
+
+	l.NewEnv("SE_GA_TEST_PORT").GetInt()
+	l.NewEnv("SE_GA_TEST_PORT").GetPort()
+
+	assert.Len(t, l.Failures(), 1)
+	assert.Equal(t, "an integer", l.Failures()[0].Expected)
+}
+
+// TestNilLoaderPanics verifies that an [Env] created through a nil [*Loader]
+// keeps the panicking behaviour of [NewEnv].
+func TestNilLoaderPanics(t *testing.T) {
+	t.Setenv("SE_GA_TEST_PORT", "http")
+
+	var l *Loader
+
+	err := recoverError(func() { l.NewEnv("SE_GA_TEST_PORT").GetPort() })
+	assert.True(t, errors.Is(err, errors.ErrInvalidEnv))
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/stellarentropy/gravity-assist-common/utils"
+)
+
+// HostPort is a network address made of a host, which is a hostname, an IP
+// address or empty for every interface, and a port.
+type HostPort struct {
+	Host string
+	Port int
+}
+
+// String renders the address as host:port, enclosing IPv6 addresses in square
+// brackets.
+func (hp HostPort) String() string {
+	return net.JoinHostPort(hp.Host, strconv.Itoa(hp.Port))
+}
+
+// MarshalText renders the address as [HostPort.String] does, so that it is
+// written as a string in JSON documents.
+func (hp HostPort) MarshalText() ([]byte, error) {
+	return []byte(hp.String()), nil
+}
+
+// Parsers of network values, for use with [Get] and [GetSlice].
+var (
+	// HostnameParser accepts hostnames as defined by RFC 1123 and IP
+	// addresses.
+	HostnameParser = Parser[string]{
+		Expected: "a hostname or an IP address",
+		Elements: "hostnames or IP addresses",
+		Parse: func(s string) (string, error) {
+			if !isHost(s) {
+				return "", fmt.Errorf("invalid hostname %q", s)
+			}
+
+			return s, nil
+		},
+	}
+
+	// HostPortParser accepts host:port addresses, see [Env.GetHostPort].
+	HostPortParser = Parser[HostPort]{
+		Expected: "a host:port address such as example.com:443 or [::1]:8080",
+		Elements: "host:port addresses such as example.com:443 or [::1]:8080",
+		Parse:    parseHostPort,
+	}
+
+	// CIDRParser accepts IPv4 and IPv6 network prefixes in CIDR notation,
+	// returned masked.
+	CIDRParser = Parser[netip.Prefix]{
+		Expected: "a CIDR prefix such as 10.0.0.0/8",
+		Elements: "CIDR prefixes such as 10.0.0.0/8",
+		Parse: func(s string) (netip.Prefix, error) {
+			p, err := netip.ParsePrefix(s)
+
+			return p.Masked(), err
+		},
+	}
+)
+
+// AbsoluteURLParser returns a [Parser] accepting absolute URLs, with a scheme
+// and a host, and with one of the given schemes if any.
+func AbsoluteURLParser(schemes ...string) Parser[*url.URL] {
+	expected := "an absolute URL such as https://example.com"
+	if len(schemes) > 0 {
+		expected = fmt.Sprintf("an absolute URL with scheme %s", strings.Join(schemes, " or "))
+	}
+
+	return Parser[*url.URL]{
+		Expected: expected,
+		Parse: func(s string) (*url.URL, error) {
+			u, err := url.Parse(s)
+			if err != nil {
+				return nil, err
+			}
+
+			if u.Scheme == "" || u.Host == "" || (u.Hostname() != "" && !isHost(u.Hostname())) {
+				return nil, fmt.Errorf("URL %q has no scheme or no valid host", u.Redacted())
+			}
+
+			if len(schemes) > 0 && !utils.StringInSlice(strings.ToLower(u.Scheme), schemes) {
+				return nil, fmt.Errorf("scheme %q is not allowed", u.Scheme)
+			}
+
+			return u, nil
+		},
+	}
+}
+
+// GetHostname retrieves a hostname, such as pcm.example.com, or an IP address
+// from the environment variable associated with the [Env] instance. Hostnames
+// must be made of dot-separated labels of letters, digits and hyphens, as
+// defined by RFC 1123. It returns an empty string if the variable is unset or
+// empty, and panics if the value is not a valid hostname.
+func (e Env) GetHostname() string {
+	return Get(e, HostnameParser)
+}
+
+// GetHostPort retrieves a network address such as pcm.example.com:443,
+// [::1]:8080 or :9090 from the environment variable associated with the [Env]
+// instance. The host may be a hostname, an IP address, with IPv6 addresses in
+// square brackets, or empty, and the port must be a number between 0 and
+// 65535. It returns the zero [HostPort] if the variable is unset or empty, and
+// panics if the value is not a valid address.
+func (e Env) GetHostPort() HostPort {
+	return Get(e, HostPortParser)
+}
+
+// GetCIDRs splits the value of the environment variable into a list of IPv4
+// or IPv6 network prefixes in CIDR notation, such as 10.0.0.0/8, as
+// [Env.GetStringSlice] does. Each prefix is masked, so that 10.1.2.3/8 is
+// returned as 10.0.0.0/8. If an element is not a valid prefix, it panics.
+func (e Env) GetCIDRs() []netip.Prefix {
+	return GetSlice(e, CIDRParser)
+}
+
+// GetAbsoluteURL retrieves an absolute URL from the environment variable
+// associated with the [Env] instance. Unlike [Env.GetURL], the value must
+// include a scheme and a host and, when schemes are given, the scheme must be
+// one of them. It returns nil if the variable is unset or empty, and panics if
+// the value is not a valid absolute URL.
+func (e Env) GetAbsoluteURL(schemes ...string) *url.URL {
+	return Get(e, AbsoluteURLParser(schemes...))
+}
+
+// parseHostPort parses a host:port address, where the host may be empty.
+func parseHostPort(s string) (HostPort, error) {
+	host, sport, err := net.SplitHostPort(s)
+	if err != nil {
+		return HostPort{}, err
+	}
+
+	port, err := strconv.Atoi(sport)
+	if err != nil || port < 0 || port > 65535 {
+		return HostPort{}, fmt.Errorf("invalid port %q", sport)
+	}
+
+	if host != "" && !isHost(host) {
+		return HostPort{}, fmt.Errorf("invalid host %q", host)
+	}
+
+	return HostPort{Host: host, Port: port}, nil
+}
+
+// isHost reports whether s is an IP address or a hostname as defined by
+// RFC 1123, with an optional trailing dot.
+func isHost(s string) bool {
+	if _, err := netip.ParseAddr(s); err == nil {
+		return true
+	}
+
+	s = strings.TrimSuffix(s, ".")
+	if s == "" || len(s) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(s, ".") {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/stellarentropy/gravity-assist-common/utils"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+)
+
+// Parsers of the values read by the getters of [Env], for use with [Get] and
+// [GetSlice].
+var (
+	// StringParser accepts any value as is.
+	StringParser = Parser[string]{
+		Expected: "a string",
+		Elements: "strings",
+		Parse: func(s string) (string, error) {
+			return s, nil
+		},
+	}
+
+	// BoolParser accepts the values of [strconv.ParseBool].
+	BoolParser = Parser[bool]{
+		Expected: "a boolean",
+		Elements: "booleans",
+		Parse:    strconv.ParseBool,
+	}
+
+	// IntParser accepts decimal integers.
+	IntParser = Parser[int]{
+		Expected: "an integer",
+		Elements: "integers",
+		Parse:    strconv.Atoi,
+	}
+
+	// Float64Parser accepts floating-point numbers.
+	Float64Parser = Parser[float64]{
+		Expected: "a floating-point number",
+		Elements: "floating-point numbers",
+		Parse: func(s string) (float64, error) {
+			return strconv.ParseFloat(s, 64)
+		},
+	}
+
+	// DurationParser accepts the durations of [time.ParseDuration].
+	DurationParser = Parser[time.Duration]{
+		Expected: "a duration such as 30s or 5m",
+		Elements: "durations such as 30s or 5m",
+		Parse:    time.ParseDuration,
+	}
+
+	// AddressParser accepts IP addresses, returned in their canonical form,
+	// and localhost.
+	AddressParser = Parser[string]{
+		Expected: "an IP address or localhost",
+		Elements: "IP addresses or localhost",
+		Parse: func(s string) (string, error) {
+			if s == "localhost" {
+				return s, nil
+			}
+
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return "", &net.ParseError{Type: "IP address", Text: s}
+			}
+
+			return ip.String(), nil
+		},
+	}
+
+	// URLParser accepts the URLs and relative references of [url.Parse].
+	URLParser = Parser[string]{
+		Expected: "a URL",
+		Elements: "URLs",
+		Parse:    parseURL,
+	}
+
+	// URLPathParser accepts the URLs and relative references of [url.Parse].
+	URLPathParser = Parser[string]{
+		Expected: "a URL path",
+		Elements: "URL paths",
+		Parse:    parseURL,
+	}
+
+	// DirectoryParser accepts the paths of existing directories.
+	DirectoryParser = Parser[string]{
+		Expected: "an existing directory",
+		Elements: "existing directories",
+		Parse: func(s string) (string, error) {
+			if !utils.IsDirectory(s) {
+				return "", errors.ErrInvalidPath
+			}
+
+			return s, nil
+		},
+	}
+
+	// FileParser accepts the paths of existing files.
+	FileParser = Parser[string]{
+		Expected: "an existing file",
+		Elements: "existing files",
+		Parse: func(s string) (string, error) {
+			if !utils.IsFile(s) {
+				return "", errors.ErrInvalidPath
+			}
+
+			return s, nil
+		},
+	}
+
+	// DirectoryOrCreateParser accepts the paths of directories, creating them
+	// along with their parents if they do not exist.
+	DirectoryOrCreateParser = Parser[string]{
+		Expected: "a creatable directory",
+		Elements: "creatable directories",
+		Parse: func(s string) (string, error) {
+			if !utils.IsDirectory(s) {
+				if err := os.MkdirAll(s, 0755); err != nil {
+					return "", errors.Wrap(errors.ErrInvalidPath, err)
+				}
+			}
+
+			return s, nil
+		},
+	}
+
+	// FileOrCreateParser accepts the paths of files, creating them if they do
+	// not exist.
+	FileOrCreateParser = Parser[string]{
+		Expected: "a creatable file",
+		Elements: "creatable files",
+		Parse: func(s string) (string, error) {
+			if !utils.IsFile(s) {
+				if err := os.MkdirAll(filepath.Base(s), 0755); err != nil {
+					return "", errors.Wrap(errors.ErrInvalidPath, err)
+				}
+
+				if _, err := os.Create(s); err != nil {
+					return "", errors.Wrap(errors.ErrInvalidPath, err)
+				}
+			}
+
+			return s, nil
+		},
+	}
+)
+
+// parseURL returns s if it is a valid URL or relative reference.
+func parseURL(s string) (string, error) {
+	if _, err := url.Parse(s); err != nil {
+		return "", err
+	}
+
+	return s, nil
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"reflect"
+)
+
+// ProfileKey is the environment variable selecting the [Profile] a service
+// runs with, which decides the defaults applied to unset variables.
+const ProfileKey = "SE_GA_PROFILE"
+
+// Profile names a kind of deployment, such as a laptop or production, each
+// with its own defaults for the variables that are not set, see
+// [Env.WithProfileDefault].
+type Profile string
+
+const (
+	// ProfileDev suits a developer laptop, and is the [DefaultProfile].
+	ProfileDev Profile = "dev"
+
+	// ProfileTest suits automated tests.
+	ProfileTest Profile = "test"
+
+	// ProfileStaging suits pre-production deployments.
+	ProfileStaging Profile = "staging"
+
+	// ProfileProd suits production deployments, with safe defaults.
+	ProfileProd Profile = "prod"
+)
+
+// DefaultProfile is the [Profile] used when [ProfileKey] is not set.
+const DefaultProfile = ProfileDev
+
+// Profiles lists every [Profile] accepted by [ProfileKey].
+var Profiles = []Profile{ProfileDev, ProfileTest, ProfileStaging, ProfileProd}
+
+// Profile returns the [Profile] selected by [ProfileKey], or [DefaultProfile]
+// if it is not set. The profile is read once, and an unknown profile is
+// recorded as a failure of the [Loader]. A nil [*Loader] reads the profile on
+// every call, panicking if it is unknown.
+func (l *Loader) Profile() Profile {
+	if l != nil && l.profile != "" {
+		return l.profile
+	}
+
+	names := make([]string, 0, len(Profiles))
+	for _, p := range Profiles {
+		names = append(names, string(p))
+	}
+
+	p := Profile(l.NewEnv(ProfileKey).
+		WithDefault(string(DefaultProfile)).
+		WithOptions(names...).
+		GetString())
+
+	if l != nil {
+		l.profile = p
+	}
+
+	return p
+}
+
+// WithProfileDefault sets a default value for the environment variable, like
+// [Env.WithDefault], but only when the active [Profile] is p, see
+// [Loader.Profile]. It must be called before [Env.WithDefault], which then
+// provides the default of the other profiles. It returns the same [Env]
+// instance to enable method chaining.
+func (e Env) WithProfileDefault(p Profile, value string) Env {
+	if e.value == "" && value != "" && e.loader.Profile() == p {
+		e.value = value
+		e.origin = OriginProfile
+	}
+
+	return e
+}
+
+// tagProfileDefaults returns the profile-specific defaults of f, held by the
+// tags named after [TagDefault] and a profile, such as default.prod.
+func tagProfileDefaults(f reflect.StructField) map[Profile]string {
+	var defaults map[Profile]string
+
+	for _, p := range Profiles {
+		if def, ok := f.Tag.Lookup(TagDefault + "." + string(p)); ok {
+			if defaults == nil {
+				defaults = map[Profile]string{}
+			}
+
+			defaults[p] = def
+		}
+	}
+
+	return defaults
+}
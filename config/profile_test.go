@@ -0,0 +1,47 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProfileDefaults verifies that the defaults of the active [Profile] take
+// precedence over the other defaults, but not over values that are set.
+func TestProfileDefaults(t *testing.T) {
+	var c struct {
+		Format  string `env:"SE_GA_TEST_FORMAT" default:"color" default.prod:"json"`
+		Sampler string `env:"SE_GA_TEST_SAMPLER" default:"always" default.prod:"traceIdRatio"`
+	}
+
+	l := NewMapLoader(map[string]string{"SE_GA_TEST_SAMPLER": "never"})
+	l.Bind(&c)
+	assert.NoError(t, l.Err())
+	assert.Equal(t, DefaultProfile, l.Profile())
+	assert.Equal(t, "color", c.Format)
+
+	l = NewMapLoader(map[string]string{ProfileKey: "prod", "SE_GA_TEST_SAMPLER": "never"})
+	l.Bind(&c)
+	assert.NoError(t, l.Err())
+	assert.Equal(t, ProfileProd, l.Profile())
+	assert.Equal(t, "json", c.Format)
+	assert.Equal(t, "never", c.Sampler)
+	assert.Equal(t, OriginProfile, l.Origins()["SE_GA_TEST_FORMAT"])
+}
+
+// TestProfileUnknown verifies that an unknown [Profile] is reported as an
+// invalid value of [ProfileKey].
+func TestProfileUnknown(t *testing.T) {
+	l := NewMapLoader(map[string]string{ProfileKey: "production"})
+	l.Profile()
+
+	var le *LoadError
+	assert.True(t, errors.As(l.Err(), &le))
+	assert.Equal(t, ProfileKey, le.Failures[0].Key)
+
+	t.Setenv(ProfileKey, "production")
+
+	err := recoverError(func() { NewEnv("SE_GA_TEST_FORMAT").WithProfileDefault(ProfileProd, "json") })
+	assert.True(t, errors.Is(err, errors.ErrInvalidEnv))
+}
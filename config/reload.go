@@ -0,0 +1,177 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Reloadable holds the current snapshot of a configuration of type T and
+// replaces it when the configuration changes, either on SIGHUP or when the
+// file selected by [ConfigFileKey] is modified. A new snapshot is only
+// published once it has been loaded and validated as a whole, so that readers
+// never observe a partially applied or invalid configuration. Subscribers are
+// notified of every published snapshot.
+type Reloadable[T any] struct {
+	load     func() (*T, error)
+	interval time.Duration
+
+	// signals replaces the SIGHUP notifications when set, and started is
+	// called once [Reloadable.Start] watches for changes, so that tests
+	// trigger reloads without signalling the process or sleeping
+	signals chan os.Signal
+	started func()
+
+	current atomic.Pointer[T]
+
+	mu          sync.Mutex
+	subscribers []func(old, new *T)
+	onError     []func(error)
+}
+
+// NewReloadable creates a [Reloadable] holding the given initial snapshot.
+// The load function is called on every reload to build a new snapshot, and
+// must return an error if the configuration is invalid, typically the one
+// returned by [Loader.Err]. The file selected by [ConfigFileKey] is checked
+// for modifications at the given interval once [Reloadable.Start] is called,
+// unless the interval is not positive.
+func NewReloadable[T any](initial *T, load func() (*T, error), interval time.Duration) *Reloadable[T] {
+	r := &Reloadable[T]{
+		load:     load,
+		interval: interval,
+	}
+
+	r.current.Store(initial)
+
+	return r
+}
+
+// Get returns the current snapshot. The snapshot must be treated as read-only,
+// and callers interested in changes should either call Get on every use or
+// register with [Reloadable.Subscribe].
+func (r *Reloadable[T]) Get() *T {
+	return r.current.Load()
+}
+
+// Subscribe registers fn to be called with the previous and the new snapshot
+// every time a snapshot is published. Subscribers are called sequentially, in
+// the order in which they were registered.
+func (r *Reloadable[T]) Subscribe(fn func(old, new *T)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subscribers = append(r.subscribers, fn)
+}
+
+// OnError registers fn to be called with the error of every failed reload
+// triggered by [Reloadable.Start], which otherwise has no caller to report
+// it to.
+func (r *Reloadable[T]) OnError(fn func(error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.onError = append(r.onError, fn)
+}
+
+// Reload loads a new snapshot, re-reading the configuration file, and
+// publishes it to readers and subscribers. If the new configuration is
+// invalid, the current snapshot is kept and the error is returned.
+func (r *Reloadable[T]) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	forgetConfigFiles()
+
+	next, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	prev := r.current.Swap(next)
+
+	for _, fn := range r.subscribers {
+		fn(prev, next)
+	}
+
+	return nil
+}
+
+// Start reloads the configuration on SIGHUP and whenever the modification time
+// of the file selected by [ConfigFileKey] changes, checked at the interval
+// given to [NewReloadable] if it is positive, until ctx is done. Failed
+// reloads are reported to the functions registered with [Reloadable.OnError].
+func (r *Reloadable[T]) Start(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	sig := r.signals
+	if sig == nil {
+		sig = make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGHUP)
+		defer signal.Stop(sig)
+	}
+
+	// Without a positive interval, the file is only re-read on SIGHUP
+	var tick <-chan time.Time
+	if r.interval > 0 {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		tick = ticker.C
+	}
+
+	modTime := configFileModTime()
+
+	if r.started != nil {
+		r.started()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sig:
+			r.reload()
+		case <-tick:
+			if mt := configFileModTime(); !mt.Equal(modTime) {
+				modTime = mt
+				r.reload()
+			}
+		}
+	}
+}
+
+// reload calls [Reloadable.Reload] and reports its error, if any.
+func (r *Reloadable[T]) reload() {
+	err := r.Reload()
+	if err == nil {
+		return
+	}
+
+	r.mu.Lock()
+	handlers := r.onError
+	r.mu.Unlock()
+
+	for _, fn := range handlers {
+		fn(err)
+	}
+}
+
+// configFileModTime returns the modification time of the file selected by
+// [ConfigFileKey], or the zero time if there is none.
+func configFileModTime() time.Time {
+	path := os.Getenv(ConfigFileKey)
+	if path == "" {
+		return time.Time{}
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return fi.ModTime()
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// reloadConfig is the configuration reloaded by the tests of [Reloadable].
+type reloadConfig struct {
+	Level string  `env:"SE_GA_TEST_LEVEL" default:"info" options:"debug,info,warn"`
+	Ratio float64 `env:"SE_GA_TEST_RATIO" default:"0.01"`
+}
+
+// loadReloadConfig binds a new [reloadConfig] from the environment.
+func loadReloadConfig() (*reloadConfig, error) {
+	c := &reloadConfig{}
+
+	return c, Bind(c)
+}
+
+// newTestReloadable writes content to a configuration file and returns a
+// [Reloadable] holding the configuration read from it.
+func newTestReloadable(t *testing.T, content string) (*Reloadable[reloadConfig], string) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	t.Setenv(ConfigFileKey, path)
+	forgetConfigFiles()
+
+	c, err := loadReloadConfig()
+	assert.NoError(t, err)
+
+	return NewReloadable(c, loadReloadConfig, 10*time.Millisecond), path
+}
+
+// startTestReloadable starts r with an injected signal channel, returned to
+// trigger reloads, and waits until it watches for changes. It is stopped at
+// the end of the test.
+func startTestReloadable(t *testing.T, r *Reloadable[reloadConfig]) chan<- os.Signal {
+	r.signals = make(chan os.Signal, 1)
+
+	started := make(chan struct{})
+	r.started = func() { close(started) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go r.Start(ctx, &wg)
+
+	t.Cleanup(func() {
+		cancel()
+		wg.Wait()
+	})
+
+	<-started
+
+	return r.signals
+}
+
+// TestReloadablePublishesValidSnapshots verifies that a reload publishes the
+// new snapshot to readers and subscribers, and that an invalid configuration
+// is rejected as a whole, keeping the current snapshot.
+func TestReloadablePublishesValidSnapshots(t *testing.T) {
+	r, path := newTestReloadable(t, "SE_GA_TEST_LEVEL: info\n")
+	assert.Equal(t, "info", r.Get().Level)
+
+	var notified []string
+	r.Subscribe(func(old, new *reloadConfig) {
+		notified = append(notified, old.Level+"->"+new.Level)
+	})
+
+	assert.NoError(t, os.WriteFile(path, []byte("SE_GA_TEST_LEVEL: debug\nSE_GA_TEST_RATIO: 0.5\n"), 0600))
+	assert.NoError(t, r.Reload())
+	assert.Equal(t, "debug", r.Get().Level)
+	assert.Equal(t, 0.5, r.Get().Ratio)
+
+	assert.NoError(t, os.WriteFile(path, []byte("SE_GA_TEST_LEVEL: verbose\nSE_GA_TEST_RATIO: 1\n"), 0600))
+	err := r.Reload()
+	assert.True(t, errors.Is(err, errors.ErrInvalidEnv))
+	assert.Equal(t, "debug", r.Get().Level)
+	assert.Equal(t, 0.5, r.Get().Ratio)
+
+	assert.Equal(t, []string{"info->debug"}, notified)
+}
+
+// TestReloadableStart verifies that a started [Reloadable] reloads when the
+// configuration file is modified and on SIGHUP, and reports failed reloads.
+func TestReloadableStart(t *testing.T) {
+	r, path := newTestReloadable(t, "SE_GA_TEST_LEVEL: info\n")
+
+	levels := make(chan string, 4)
+	r.Subscribe(func(_, new *reloadConfig) { levels <- new.Level })
+
+	errc := make(chan error, 4)
+	r.OnError(func(err error) { errc <- err })
+
+	sig := startTestReloadable(t, r)
+
+	assert.NoError(t, os.WriteFile(path, []byte("SE_GA_TEST_LEVEL: warn\n"), 0600))
+	future := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(path, future, future))
+
+	select {
+	case level := <-levels:
+		assert.Equal(t, "warn", level)
+	case <-time.After(5 * time.Second):
+		t.Fatal("configuration file change not picked up")
+	}
+
+	assert.NoError(t, os.WriteFile(path, []byte("SE_GA_TEST_LEVEL: loud\n"), 0600))
+	sig <- syscall.SIGHUP
+
+	select {
+	case err := <-errc:
+		assert.True(t, errors.Is(err, errors.ErrInvalidEnv))
+	case <-time.After(5 * time.Second):
+		t.Fatal("SIGHUP not handled")
+	}
+
+	assert.Equal(t, "warn", r.Get().Level)
+}
+
+// TestReloadableStartWithoutInterval verifies that a [Reloadable] without a
+// positive interval does not poll the configuration file, but still reloads
+// on SIGHUP.
+func TestReloadableStartWithoutInterval(t *testing.T) {
+	r, path := newTestReloadable(t, "SE_GA_TEST_LEVEL: info\n")
+	r.interval = 0
+
+	levels := make(chan string, 4)
+	r.Subscribe(func(_, new *reloadConfig) { levels <- new.Level })
+
+	sig := startTestReloadable(t, r)
+
+	assert.NoError(t, os.WriteFile(path, []byte("SE_GA_TEST_LEVEL: warn\n"), 0600))
+	sig <- syscall.SIGHUP
+
+	select {
+	case level := <-levels:
+		assert.Equal(t, "warn", level)
+	case <-time.After(5 * time.Second):
+		t.Fatal("SIGHUP not handled")
+	}
+}
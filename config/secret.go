@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/stellarentropy/gravity-assist-common/consts"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+)
+
+// SecretFileSuffix is appended to the key of an environment variable to name
+// the variable holding the path of a file to read its value from, such as
+// SE_GA_JWT_SIGNING_KEY_FILE for SE_GA_JWT_SIGNING_KEY. This allows secrets
+// mounted as files, for example from Kubernetes secrets or GCP Secret Manager
+// volumes, to be used without copying them into the environment. The variable
+// itself takes precedence over its _FILE variant.
+const SecretFileSuffix = "_FILE"
+
+// secretFileMaxSize bounds the size of a secret file, so that pointing a
+// _FILE variable at a large file by mistake does not exhaust memory.
+const secretFileMaxSize = 64 << 10
+
+// secretFileForbiddenPerm holds the permission bits a secret file must not
+// have: a file writable by its group or by other users could be tampered with.
+const secretFileForbiddenPerm os.FileMode = 0o022
+
+// secretKeyWords lists the words that, when found as an underscore-separated
+// segment of an environment variable key, mark its value as a secret.
+var secretKeyWords = []string{
+	"SECRET",
+	"SECRETS",
+	"TOKEN",
+	"PASSWORD",
+	"PASSWD",
+	"PASSPHRASE",
+	"KEY",
+	"APIKEY",
+	"CREDENTIAL",
+	"CREDENTIALS",
+	"SIGNATURE",
+	"HMAC",
+	"PRIVATE",
+}
+
+// IsSecretKey reports whether an environment variable key looks like it names
+// a secret, such as SE_GA_JWT_SIGNING_KEY or SE_GA_PCM_TOKEN. The key is split
+// on underscores and each segment is compared case-insensitively against a
+// list of well-known words.
+func IsSecretKey(key string) bool {
+	for _, segment := range strings.Split(strings.ToUpper(key), "_") {
+		for _, word := range secretKeyWords {
+			if segment == word {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// readSecretFile reads the secret held by the file at path, named by the
+// variable fileKey, with surrounding whitespace trimmed. The file must be a
+// regular file of at most 64 KiB that is writable by its owner only.
+func readSecretFile(fileKey string, path string) (string, *errors.Error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", newSecretFileError(fileKey, path, err)
+	}
+
+	if !fi.Mode().IsRegular() {
+		return "", newSecretFileError(fileKey, path, fmt.Errorf("not a regular file"))
+	}
+
+	if perm := fi.Mode().Perm(); perm&secretFileForbiddenPerm != 0 {
+		return "", newSecretFileError(fileKey, path, fmt.Errorf("permissions %#o allow writes by group or others", perm))
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", newSecretFileError(fileKey, path, err)
+	}
+	defer f.Close()
+
+	b, err := io.ReadAll(io.LimitReader(f, secretFileMaxSize+1))
+	if err != nil {
+		return "", newSecretFileError(fileKey, path, err)
+	}
+
+	if len(b) > secretFileMaxSize {
+		return "", newSecretFileError(fileKey, path, fmt.Errorf("larger than %d bytes", secretFileMaxSize))
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+// newSecretFileError creates the error reported when the secret file at path,
+// named by the variable fileKey, cannot be used. The path is not a secret, but
+// the error never includes the content of the file.
+func newSecretFileError(fileKey string, path string, cause error) *errors.Error {
+	return errors.NewError(errors.ErrInvalidSecretFile).
+		WithSource(consts.SE).
+		WithField("env", fileKey).
+		WithField("value", path).
+		WithField("expected", "a readable file not writable by group or others").
+		WithCause(cause)
+}
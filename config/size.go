@@ -0,0 +1,154 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+)
+
+// ByteSize is a size in bytes, read from values such as 512, 10KB or 64MiB.
+type ByteSize int64
+
+// Byte sizes in decimal (SI) and binary (IEC) units.
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000 * Byte
+	MB ByteSize = 1000 * KB
+	GB ByteSize = 1000 * MB
+	TB ByteSize = 1000 * GB
+
+	KiB ByteSize = 1024 * Byte
+	MiB ByteSize = 1024 * KiB
+	GiB ByteSize = 1024 * MiB
+	TiB ByteSize = 1024 * GiB
+)
+
+// byteUnits maps the units accepted by [ParseByteSize], in lower case, to
+// their size.
+var byteUnits = map[string]ByteSize{
+	"":    Byte,
+	"b":   Byte,
+	"k":   KiB,
+	"kb":  KB,
+	"kib": KiB,
+	"m":   MiB,
+	"mb":  MB,
+	"mib": MiB,
+	"g":   GiB,
+	"gb":  GB,
+	"gib": GiB,
+	"t":   TiB,
+	"tb":  TB,
+	"tib": TiB,
+}
+
+// ParseByteSize parses a size made of a number, possibly with a fractional
+// part, and an optional unit: B, KB, MB, GB and TB for powers of 1000, and
+// KiB, MiB, GiB and TiB, or their single letter abbreviations K, M, G and T,
+// for powers of 1024. Units are case-insensitive and may be separated from
+// the number by spaces. The size is computed exactly and must be a whole
+// number of bytes, so that 1.5KiB is accepted but 1.5B is not.
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r == '.')
+	})
+	if i < 0 {
+		i = len(s)
+	}
+
+	number, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+
+	// The number is read as an integer mantissa, scaled down by a power of
+	// ten for the digits of its fractional part
+	whole, frac, _ := strings.Cut(number, ".")
+	frac = strings.TrimRight(frac, "0")
+	if whole+frac == "" || strings.Contains(frac, ".") || len(frac) >= len(pow10) {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+
+	mantissa, err := strconv.ParseUint(whole+frac, 10, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, fmt.Errorf("size %q overflows", s)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+
+	size, ok := byteUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q in size %q", s[i:], s)
+	}
+
+	// The product is computed on 128 bits, so that the division by the scale
+	// is exact
+	hi, lo := bits.Mul64(mantissa, uint64(size))
+	scale := pow10[len(frac)]
+	if hi >= scale {
+		return 0, fmt.Errorf("size %q overflows", s)
+	}
+
+	bytes, rem := bits.Div64(hi, lo, scale)
+	if rem != 0 {
+		return 0, fmt.Errorf("size %q is not a whole number of bytes", s)
+	}
+	if bytes > math.MaxInt64 {
+		return 0, fmt.Errorf("size %q overflows", s)
+	}
+
+	return ByteSize(bytes), nil
+}
+
+// pow10 holds the powers of ten that fit in a uint64, by which the mantissa
+// of a size is scaled down.
+var pow10 = func() []uint64 {
+	p := []uint64{1}
+	for len(p) < 20 {
+		p = append(p, p[len(p)-1]*10)
+	}
+
+	return p
+}()
+
+// String renders the size with the largest binary unit dividing it exactly,
+// such as 64MiB, or in bytes, such as 1500B.
+func (b ByteSize) String() string {
+	for _, u := range []struct {
+		size ByteSize
+		name string
+	}{{TiB, "TiB"}, {GiB, "GiB"}, {MiB, "MiB"}, {KiB, "KiB"}} {
+		if b != 0 && b%u.size == 0 {
+			return strconv.FormatInt(int64(b/u.size), 10) + u.name
+		}
+	}
+
+	return strconv.FormatInt(int64(b), 10) + "B"
+}
+
+// MarshalText renders the size as [ByteSize.String] does, so that it is
+// written as a string in JSON documents.
+func (b ByteSize) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// ByteSizeParser accepts the sizes of [ParseByteSize], for use with [Get] and
+// [GetSlice].
+var ByteSizeParser = Parser[ByteSize]{
+	Expected: "a size such as 512, 10KB or 64MiB",
+	Elements: "sizes such as 512, 10KB or 64MiB",
+	Parse:    ParseByteSize,
+}
+
+// GetByteSize retrieves a size in bytes, such as 64MiB, from the environment
+// variable associated with the [Env] instance, see [ParseByteSize]. If the
+// variable is unset or empty, it defaults to 0. If the value is not a valid
+// size, it panics.
+func (e Env) GetByteSize() ByteSize {
+	return Get(e, ByteSizeParser)
+}
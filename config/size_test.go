@@ -0,0 +1,62 @@
+package config
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseByteSize verifies that sizes are parsed with decimal and binary
+// units, case-insensitively and exactly, and rendered with the largest exact
+// binary unit.
+func TestParseByteSize(t *testing.T) {
+	for s, want := range map[string]ByteSize{
+		"512":                 512,
+		"10KB":                10 * KB,
+		"64MiB":               64 * MiB,
+		"64 mib":              64 * MiB,
+		"1.5GiB":              1536 * MiB,
+		"2g":                  2 * GiB,
+		"1TB":                 TB,
+		"0":                   0,
+		" 8 KiB ":             8 * KiB,
+		"8388607TiB":          8388607 * TiB,
+		"0.5KiB":              512,
+		"1.50KB":              1500,
+		".5MB":                500 * KB,
+		"9223372036854775807": math.MaxInt64,
+		"9007199254740993":    1<<53 + 1,
+	} {
+		b, err := ParseByteSize(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, want, b, s)
+	}
+
+	// 8388608TiB is 8EiB, or 2^63, one byte past the largest size, and
+	// fractions must amount to whole bytes
+	for _, s := range []string{"", "MiB", "64XB", "-1KB", "1e3", "1.2.3MB", "99999999TiB", "8388608TiB",
+		"9223372036854775808", "18446744073709551616", "1.5B", "0.3KiB", ".", "1.KB."} {
+		_, err := ParseByteSize(s)
+		assert.Error(t, err, s)
+	}
+
+	assert.Equal(t, "64MiB", (64 * MiB).String())
+	assert.Equal(t, "1500B", (1500 * Byte).String())
+	assert.Equal(t, "0B", ByteSize(0).String())
+}
+
+// TestEnvByteSize verifies that [Env.GetByteSize] reads sizes and panics with
+// [errors.ErrInvalidEnv] on invalid ones.
+func TestEnvByteSize(t *testing.T) {
+	t.Setenv("SE_GA_TEST_MAX_BODY", "64MiB")
+	t.Setenv("SE_GA_TEST_INVALID", "lots")
+
+	assert.Equal(t, 64*MiB, NewEnv("SE_GA_TEST_MAX_BODY").GetByteSize())
+	assert.Equal(t, 10*KB, NewEnv("SE_GA_TEST_UNSET").WithDefault("10KB").GetByteSize())
+	assert.Zero(t, NewEnv("SE_GA_TEST_UNSET").GetByteSize())
+
+	err := recoverError(func() { NewEnv("SE_GA_TEST_INVALID").GetByteSize() })
+	assert.True(t, errors.Is(err, errors.ErrInvalidEnv))
+}
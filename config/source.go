@@ -0,0 +1,256 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/stellarentropy/gravity-assist-common/consts"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+)
+
+// ConfigFileKey is the environment variable selecting an optional
+// configuration file, whose values are layered between the defaults and the
+// environment variables. The format of the file is chosen from its extension:
+// .yaml or .yml for YAML, .json for JSON and .env for dotenv files.
+const ConfigFileKey = "SE_GA_CONFIG_FILE"
+
+// Origin identifies the layer a configuration value was read from. Layers
+// take precedence over each other in the following order, highest first:
+// environment variables, the configuration file, the defaults of the active
+// [Profile] and the other defaults.
+type Origin string
+
+const (
+	// OriginNone is reported for variables that are not set in any layer.
+	OriginNone Origin = ""
+
+	// OriginDefault is reported for values set by [Env.WithDefault].
+	OriginDefault Origin = "default"
+
+	// OriginProfile is reported for values set by [Env.WithProfileDefault]
+	// for the active [Profile].
+	OriginProfile Origin = "profile"
+
+	// OriginFile is reported for values read from the file selected by
+	// [ConfigFileKey].
+	OriginFile Origin = "file"
+
+	// OriginEnv is reported for values read from the environment variables
+	// of the process.
+	OriginEnv Origin = "env"
+
+	// OriginSecretFile is reported for values read from the file named by the
+	// _FILE variant of a variable, see [SecretFileSuffix].
+	OriginSecretFile Origin = "secret-file"
+
+	// OriginMap is reported for values read from the map of a [Loader]
+	// created with [NewMapLoader].
+	OriginMap Origin = "map"
+)
+
+// configFiles caches the parsed configuration files by path, so that a file is
+// read once however many variables are looked up in it.
+var configFiles = struct {
+	sync.Mutex
+	values map[string]map[string]string
+}{values: map[string]map[string]string{}}
+
+// lookup resolves the value of the given key across the layers above the
+// defaults, see [lookupLayers]. If the key is not set in any of them, the
+// value is read from the file named by the _FILE variant of the key, if set,
+// see [SecretFileSuffix].
+func lookup(values map[string]string, key string) (string, Origin, *errors.Error) {
+	value, origin, err := lookupLayers(values, key)
+	if err != nil || origin != OriginNone {
+		return value, origin, err
+	}
+
+	fileKey := key + SecretFileSuffix
+
+	path, _, err := lookupLayers(values, fileKey)
+	if err != nil || path == "" {
+		return "", OriginNone, err
+	}
+
+	value, err = readSecretFile(fileKey, path)
+	if err != nil {
+		return "", OriginNone, err
+	}
+
+	return value, OriginSecretFile, nil
+}
+
+// lookupLayers resolves the value of the given key from the environment
+// variables of the process first, then from the configuration file selected
+// by [ConfigFileKey]. When values is not nil, it replaces both layers. Empty
+// values are treated as unset, so that they fall through to the next layer.
+func lookupLayers(values map[string]string, key string) (string, Origin, *errors.Error) {
+	if values != nil {
+		if v := values[key]; v != "" {
+			return v, OriginMap, nil
+		}
+		return "", OriginNone, nil
+	}
+
+	if v := os.Getenv(key); v != "" {
+		return v, OriginEnv, nil
+	}
+
+	path := os.Getenv(ConfigFileKey)
+	if path == "" {
+		return "", OriginNone, nil
+	}
+
+	file, err := readConfigFile(path)
+	if err != nil {
+		return "", OriginNone, err
+	}
+
+	if v := file[key]; v != "" {
+		return v, OriginFile, nil
+	}
+
+	return "", OriginNone, nil
+}
+
+// readConfigFile returns the values held by the configuration file at path,
+// parsing it on first use.
+func readConfigFile(path string) (map[string]string, *errors.Error) {
+	configFiles.Lock()
+	defer configFiles.Unlock()
+
+	if values, ok := configFiles.values[path]; ok {
+		return values, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, newConfigFileError(path, err)
+	}
+
+	values, err := parseConfigFile(filepath.Ext(path), b)
+	if err != nil {
+		return nil, newConfigFileError(path, err)
+	}
+
+	configFiles.values[path] = values
+
+	return values, nil
+}
+
+// forgetConfigFiles drops the parsed configuration files from the cache, so
+// that they are read again on their next use.
+func forgetConfigFiles() {
+	configFiles.Lock()
+	defer configFiles.Unlock()
+
+	configFiles.values = map[string]map[string]string{}
+}
+
+// parseConfigFile parses the content of a configuration file with the format
+// selected by its extension ext.
+func parseConfigFile(ext string, b []byte) (map[string]string, error) {
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		var raw map[string]any
+		if err := yaml.Unmarshal(b, &raw); err != nil {
+			return nil, err
+		}
+		return flattenValues(raw)
+
+	case ".json":
+		var raw map[string]any
+		if err := json.Unmarshal(b, &raw); err != nil {
+			return nil, err
+		}
+		return flattenValues(raw)
+
+	case ".env":
+		return parseDotEnv(b)
+	}
+
+	return nil, fmt.Errorf("unsupported file extension %q", ext)
+}
+
+// flattenValues converts the scalar values of a decoded YAML or JSON document
+// into their string representation, as they would be set in the environment.
+func flattenValues(raw map[string]any) (map[string]string, error) {
+	values := make(map[string]string, len(raw))
+
+	for k, v := range raw {
+		switch x := v.(type) {
+		case nil:
+			values[k] = ""
+		case string:
+			values[k] = x
+		case bool, int, int64, uint64, float64:
+			values[k] = fmt.Sprint(x)
+		default:
+			return nil, fmt.Errorf("value of %s is not a scalar", k)
+		}
+	}
+
+	return values, nil
+}
+
+// parseDotEnv parses a dotenv file made of KEY=VALUE lines. Blank lines and
+// lines starting with # are ignored, an optional "export " prefix is accepted
+// and values may be enclosed in single or double quotes.
+func parseDotEnv(b []byte) (map[string]string, error) {
+	values := map[string]string{}
+
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		k, v, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d is not a KEY=VALUE assignment", n)
+		}
+
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+
+		switch {
+		case len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"':
+			uv, err := strconv.Unquote(v)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+			v = uv
+		case len(v) >= 2 && v[0] == '\'' && v[len(v)-1] == '\'':
+			v = v[1 : len(v)-1]
+		}
+
+		values[k] = v
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// newConfigFileError creates the error reported when the configuration file at
+// path cannot be used.
+func newConfigFileError(path string, cause error) *errors.Error {
+	return errors.NewError(errors.ErrInvalidConfigFile).
+		WithSource(consts.SE).
+		WithField("env", ConfigFileKey).
+		WithField("value", path).
+		WithField("expected", "a readable YAML, JSON or .env file").
+		WithCause(cause)
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeConfigFile writes content to a file with the given name in a temporary
+// directory and selects it through [ConfigFileKey].
+func writeConfigFile(t *testing.T, name string, content string) {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	t.Setenv(ConfigFileKey, path)
+}
+
+// TestConfigFileFormats verifies that YAML, JSON and dotenv files are parsed
+// into the same values.
+func TestConfigFileFormats(t *testing.T) {
+	for name, content := range map[string]string{
+		"config.yaml": "SE_GA_TEST_FORMAT: json\nSE_GA_TEST_WORKERS: 8\nSE_GA_TEST_ENABLED: false\n",
+		"config.json": `{"SE_GA_TEST_FORMAT": "json", "SE_GA_TEST_WORKERS": 8, "SE_GA_TEST_ENABLED": false}`,
+		"config.env":  "# local overrides\nexport SE_GA_TEST_FORMAT=\"json\"\nSE_GA_TEST_WORKERS=8\n\nSE_GA_TEST_ENABLED='false'\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			writeConfigFile(t, name, content)
+
+			assert.Equal(t, "json", NewEnv("SE_GA_TEST_FORMAT").GetString())
+			assert.Equal(t, 8, NewEnv("SE_GA_TEST_WORKERS").GetInt())
+			assert.False(t, NewEnv("SE_GA_TEST_ENABLED").WithDefault("true").GetBool())
+		})
+	}
+}
+
+// TestConfigFileLayers verifies that environment variables take precedence
+// over the configuration file, which takes precedence over the defaults, and
+// that each value records the layer it came from.
+func TestConfigFileLayers(t *testing.T) {
+	writeConfigFile(t, "config.yaml", "SE_GA_TEST_FORMAT: json\nSE_GA_TEST_NAME: file\n")
+	t.Setenv("SE_GA_TEST_NAME", "env")
+
+	e := NewEnv("SE_GA_TEST_NAME").WithDefault("default")
+	assert.Equal(t, "env", e.GetString())
+	assert.Equal(t, OriginEnv, e.Origin())
+
+	e = NewEnv("SE_GA_TEST_FORMAT").WithDefault("color")
+	assert.Equal(t, "json", e.GetString())
+	assert.Equal(t, OriginFile, e.Origin())
+
+	e = NewEnv("SE_GA_TEST_TIMEOUT").WithDefault("30s")
+	assert.Equal(t, "30s", e.GetString())
+	assert.Equal(t, OriginDefault, e.Origin())
+
+	assert.Equal(t, OriginNone, NewEnv("SE_GA_TEST_UNSET").Origin())
+}
+
+// TestConfigFileInvalid verifies that an unreadable or malformed configuration
+// file is reported once against [ConfigFileKey] by a [Loader], and panics
+// otherwise.
+func TestConfigFileInvalid(t *testing.T) {
+	writeConfigFile(t, "config.yaml", "SE_GA_TEST_HOSTS:\n  - a\n  - b\n")
+
+	l := NewLoader()
+	l.NewEnv("SE_GA_TEST_FORMAT")
+	l.NewEnv("SE_GA_TEST_NAME")
+
+	assert.Len(t, l.Failures(), 1)
+	assert.Equal(t, ConfigFileKey, l.Failures()[0].Key)
+	assert.True(t, errors.Is(l.Err(), errors.ErrInvalidConfigFile))
+
+	t.Setenv(ConfigFileKey, filepath.Join(t.TempDir(), "missing.json"))
+
+	err := recoverError(func() { NewEnv("SE_GA_TEST_FORMAT") })
+	assert.True(t, errors.Is(err, errors.ErrInvalidConfigFile))
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// InRange accepts the values between min and max, inclusive, such as ratios
+// with InRange(0.0, 1.0) or timeouts with InRange(time.Second, time.Minute).
+func InRange[T cmp.Ordered](min, max T) Validator[T] {
+	return Validator[T]{
+		Expected: fmt.Sprintf("between %v and %v", min, max),
+		Check: func(v T) bool {
+			return v >= min && v <= max
+		},
+	}
+}
+
+// Matches accepts the strings matching the regular expression pattern. The
+// pattern is not anchored, so it must start with ^ and end with $ to match
+// whole values. It panics if pattern is not a valid regular expression.
+func Matches(pattern string) Validator[string] {
+	re := regexp.MustCompile(pattern)
+
+	return Validator[string]{
+		Expected: fmt.Sprintf("matching %q", pattern),
+		Check:    re.MatchString,
+	}
+}
+
+// OneOf accepts the values equal to one of values.
+func OneOf[T comparable](values ...T) Validator[T] {
+	return Validator[T]{
+		Expected: fmt.Sprintf("among %v", values),
+		Check: func(v T) bool {
+			for _, value := range values {
+				if v == value {
+					return true
+				}
+			}
+
+			return false
+		},
+	}
+}
+
+// NonEmpty accepts the strings that are not made of whitespace only.
+func NonEmpty() Validator[string] {
+	return Validator[string]{
+		Expected: "that is not blank",
+		Check: func(v string) bool {
+			return strings.TrimSpace(v) != ""
+		},
+	}
+}
+
+// Predicate accepts the values for which fn returns true. The expected
+// description qualifies the accepted values in the error reported for the
+// others, such as "that is positive".
+func Predicate[T any](expected string, fn func(T) bool) Validator[T] {
+	return Validator[T]{
+		Expected: expected,
+		Check:    fn,
+	}
+}
@@ -0,0 +1,145 @@
+//go:generate go run ../cmd/componentgen -name debug
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stellarentropy/gravity-assist-common/config"
+	"github.com/stellarentropy/gravity-assist-common/config/common"
+	"github.com/stellarentropy/gravity-assist-common/consts"
+	"github.com/stellarentropy/gravity-assist-common/errors"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// current holds the configuration served at /debug/config, as passed to
+// [Start] and updated by [Follow].
+var current atomic.Pointer[common_config.CommonConfig]
+
+// Follow keeps the configuration served at /debug/config in line with the
+// configuration held by r as it is reloaded.
+func Follow(r *config.Reloadable[common_config.CommonConfig]) {
+	r.Subscribe(func(_, c *common_config.CommonConfig) {
+		current.Store(c)
+	})
+}
+
+func getRouter() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Use(errors.Recoverer(componentName, logger.Logger))
+
+	// Serve the effective configuration, with its secrets masked
+	router.Get("/debug/config", func(w http.ResponseWriter, r *http.Request) {
+		dump, err := current.Load().Dump()
+		if err != nil {
+			_ = errors.WriteProblem(w, r, errors.Wrap(errors.ErrResponseWriting, err))
+			return
+		}
+
+		w.Header().Set("Content-Type", consts.MIMEApplicationJSON)
+		_, _ = w.Write(dump)
+	})
+
+	logger.LogRoutes(router)
+
+	return router
+}
+
+func Listen(ctx context.Context, c *common_config.CommonConfig) error {
+	listener, err := listen(c)
+	if err != nil {
+		return err
+	}
+
+	return serve(ctx, listener, c)
+}
+
+// listen binds the listener of the debug server.
+func listen(c *common_config.CommonConfig) (net.Listener, error) {
+	current.CompareAndSwap(nil, c)
+
+	return net.Listen("tcp",
+		net.JoinHostPort(
+			c.DebugListenAddress,
+			fmt.Sprintf("%d", c.DebugListenPort)))
+}
+
+// serve runs the debug server on listener until ctx is done.
+func serve(ctx context.Context, listener net.Listener, c *common_config.CommonConfig) error {
+	// Log the starting of the debug server
+	logger.Info().
+		Str("address", listener.Addr().(*net.TCPAddr).IP.String()).
+		Int("port", listener.Addr().(*net.TCPAddr).Port).
+		Msg("starting debug server")
+
+	router := getRouter()
+
+	// Get the address of the server
+	addr := listener.Addr().(*net.TCPAddr).String()
+
+	server := &http.Server{
+		Addr:         addr,
+		Handler:      h2c.NewHandler(router, &http2.Server{}),
+		ReadTimeout:  c.DebugReadTimeout,
+		WriteTimeout: c.DebugWriteTimeout,
+	}
+
+	// Start a new goroutine that listens for the context cancellation signal
+	go func() {
+		// Wait for the context to be done
+		<-ctx.Done()
+		// Log the shutting down of the debug server
+		logger.Info().Msg("shutting down debug server")
+
+		tctx, cancel := context.WithTimeout(context.Background(), c.GracefulShutdownTimeout)
+		defer cancel()
+
+		// Attempt to gracefully shut down the server and log any errors
+		if err := server.Shutdown(tctx); err != nil {
+			logger.Error().Err(err).Msg("error shutting down debug server")
+		}
+	}()
+
+	// Start the server and return any errors encountered
+	return server.Serve(listener)
+}
+
+// start starts the debug server on behalf of [Component], whose Start cannot
+// return the error, panicking if it cannot.
+func start(ctx context.Context, wg *sync.WaitGroup, c *common_config.CommonConfig) {
+	if err := Start(ctx, wg, c); err != nil {
+		panic(err)
+	}
+}
+
+// Start binds the listener of the debug server, returning the error if it
+// cannot, and serves it in the background. A failure of the server once
+// started is logged as a structured error and still takes down the process.
+func Start(ctx context.Context, wg *sync.WaitGroup, c *common_config.CommonConfig) error {
+	listener, err := listen(c)
+	if err != nil {
+		logger.Error().Stack().Err(err).Msg("error starting debug server")
+		return err
+	}
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		defer errors.Repanic(componentName, logger.Logger)
+
+		if err := serve(ctx, listener, c); err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, http.ErrServerClosed) {
+			panic(err)
+		}
+	}()
+
+	return nil
+}
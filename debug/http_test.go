@@ -0,0 +1,54 @@
+package debug
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stellarentropy/gravity-assist-common/config/common"
+	"github.com/stellarentropy/gravity-assist-common/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConfigRoute verifies that the effective configuration is served at
+// /debug/config with the source of each value.
+func TestConfigRoute(t *testing.T) {
+	c, err := common_config.FromMap(map[string]string{"SE_GA_LOG_FORMAT": "json"})
+	assert.NoError(t, err)
+
+	current.Store(c)
+
+	w := httptest.NewRecorder()
+	getRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/config", nil))
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, consts.MIMEApplicationJSON, resp.Header.Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `{"key":"SE_GA_LOG_FORMAT","field":"LogFormat","value":"json","source":"map"}`)
+	assert.Contains(t, w.Body.String(), `{"key":"SE_GA_HEALTH_LISTEN_PORT","field":"HealthListenPort","value":1234,"source":"default"}`)
+}
+
+// TestStartPortInUse verifies that [Start] fails when its port is already
+// bound, instead of leaving the service running without a debug server.
+func TestStartPortInUse(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	c, err := common_config.FromMap(map[string]string{
+		"SE_GA_DEBUG_LISTEN_ADDRESS": "127.0.0.1",
+		"SE_GA_DEBUG_LISTEN_PORT":    strconv.Itoa(listener.Addr().(*net.TCPAddr).Port),
+	})
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	assert.Error(t, Start(context.Background(), &wg, c))
+
+	wg.Wait()
+}
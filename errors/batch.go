@@ -0,0 +1,245 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// ItemError records the failure of a single item of a batch operation,
+// identified by its position in the batch and, optionally, by its id.
+type ItemError struct {
+	Index int
+	ID    string
+	Err   error
+}
+
+// Error renders the position and id of the item followed by its error, for
+// example "item 3 (id r-42): error parsing request".
+func (i *ItemError) Error() string {
+	var b strings.Builder
+
+	b.WriteString("item ")
+	b.WriteString(strconv.Itoa(i.Index))
+
+	if i.ID != "" {
+		b.WriteString(" (id ")
+		b.WriteString(i.ID)
+		b.WriteByte(')')
+	}
+
+	b.WriteString(": ")
+	b.WriteString(i.Err.Error())
+
+	return b.String()
+}
+
+// Unwrap exposes the error of the item to [Is] and [As].
+func (i *ItemError) Unwrap() error {
+	return i.Err
+}
+
+// MarshalJSON encodes the item as a JSON object containing its index, id,
+// [Code] and message. As in a [Problem], the message only exposes the error
+// itself for client errors, and the title of its sentinel otherwise, so that
+// internal causes are not disclosed.
+func (i *ItemError) MarshalJSON() ([]byte, error) {
+	p := NewProblem(i.Err)
+
+	message := p.Detail
+	if message == "" {
+		message = p.Title
+	}
+
+	return json.Marshal(struct {
+		Index   int    `json:"index"`
+		ID      string `json:"id,omitempty"`
+		Code    Code   `json:"code,omitempty"`
+		Message string `json:"message"`
+	}{
+		Index:   i.Index,
+		ID:      i.ID,
+		Code:    CodeOf(i.Err),
+		Message: message,
+	})
+}
+
+// BatchError aggregates the failures of a batch operation, such as validating
+// or forwarding a batch of reports, where some items may fail while others
+// succeed. It records which item failed with which [error] and matches [Is]
+// for its sentinel and for the error of any item. A BatchError is safe for
+// concurrent use, so that items processed in parallel can report to it.
+type BatchError struct {
+	sentinel error
+	total    int
+
+	lock  sync.Mutex
+	items []*ItemError
+}
+
+// NewBatchError creates a new [*BatchError] for a batch of total items. The
+// sentinel describes the batch operation as a whole, for example
+// [ErrPCMForwarding].
+func NewBatchError(sentinel error, total int) *BatchError {
+	return &BatchError{
+		sentinel: sentinel,
+		total:    total,
+	}
+}
+
+// Add records the failure of the item at index, with an optional id. Nil
+// errors are ignored, so that the result of processing every item can be
+// passed unconditionally. It returns the same [*BatchError] to enable method
+// chaining.
+func (b *BatchError) Add(index int, id string, err error) *BatchError {
+	if err == nil {
+		return b
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.items = append(b.items, &ItemError{Index: index, ID: id, Err: err})
+
+	return b
+}
+
+// Items returns the failed items ordered by index.
+func (b *BatchError) Items() []*ItemError {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	items := make([]*ItemError, len(b.items))
+	copy(items, b.items)
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Index < items[j].Index
+	})
+
+	return items
+}
+
+// Failed returns the number of failed items.
+func (b *BatchError) Failed() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return len(b.items)
+}
+
+// Total returns the number of items in the batch.
+func (b *BatchError) Total() int {
+	return b.total
+}
+
+// Err returns the [*BatchError] as an [error] if at least one item failed, or
+// nil otherwise, following the convention of [Wrap].
+func (b *BatchError) Err() error {
+	if b.Failed() == 0 {
+		return nil
+	}
+
+	return b
+}
+
+// Summary returns a short description of the outcome of the batch, such as
+// "3 of 10 items failed", suitable for log messages.
+func (b *BatchError) Summary() string {
+	return fmt.Sprintf("%d of %d items failed", b.Failed(), b.total)
+}
+
+// Error renders the sentinel message and the summary of the batch, followed
+// by the error of every failed item on its own line.
+func (b *BatchError) Error() string {
+	var sb strings.Builder
+
+	if b.sentinel != nil {
+		sb.WriteString(b.sentinel.Error())
+		sb.WriteString(": ")
+	}
+
+	sb.WriteString(b.Summary())
+
+	for _, item := range b.Items() {
+		sb.WriteString("\n")
+		sb.WriteString(item.Error())
+	}
+
+	return sb.String()
+}
+
+// Unwrap exposes the sentinel and the failed items to [Is] and [As].
+func (b *BatchError) Unwrap() []error {
+	items := b.Items()
+	errl := make([]error, 0, len(items)+1)
+
+	if b.sentinel != nil {
+		errl = append(errl, b.sentinel)
+	}
+
+	for _, item := range items {
+		errl = append(errl, item)
+	}
+
+	return errl
+}
+
+// MarshalJSON encodes the batch as a JSON object containing the [Code] and
+// message of its sentinel, the total and failed counts, and the failed items.
+func (b *BatchError) MarshalJSON() ([]byte, error) {
+	je := struct {
+		Code    Code         `json:"code,omitempty"`
+		Message string       `json:"message,omitempty"`
+		Total   int          `json:"total"`
+		Failed  int          `json:"failed"`
+		Items   []*ItemError `json:"items"`
+	}{
+		Code:  CodeOf(b.sentinel),
+		Total: b.total,
+		Items: b.Items(),
+	}
+
+	je.Failed = len(je.Items)
+
+	if b.sentinel != nil {
+		je.Message = b.sentinel.Error()
+	}
+
+	return json.Marshal(je)
+}
+
+// MarshalZerologObject writes the summary counts of the batch and the number
+// of failures per [Code] to a log event, without listing every item. Failures
+// without a [Code] are counted as "unknown".
+func (b *BatchError) MarshalZerologObject(e *zerolog.Event) {
+	items := b.Items()
+	counts := make(map[string]int)
+
+	for _, item := range items {
+		code := string(CodeOf(item.Err))
+		if code == "" {
+			code = "unknown"
+		}
+		counts[code]++
+	}
+
+	keys := make([]string, 0, len(counts))
+	for code := range counts {
+		keys = append(keys, code)
+	}
+	sort.Strings(keys)
+
+	codes := zerolog.Dict()
+	for _, code := range keys {
+		codes.Int(code, counts[code])
+	}
+
+	e.Int("total", b.total).
+		Int("failed", len(items)).
+		Dict("codes", codes)
+}
@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBatchError verifies that a [*BatchError] records failed items by index
+// and id, matches [Is] for its sentinel and any item error, and is nil when no
+// item failed.
+func TestBatchError(t *testing.T) {
+	assert.NoError(t, NewBatchError(ErrPCMForwarding, 3).Add(0, "r-0", nil).Err())
+
+	batch := NewBatchError(ErrPCMForwarding, 10).
+		Add(7, "r-7", ErrRequestParsing).
+		Add(2, "r-2", Wrap(ErrSignatureMismatch, fmt.Errorf("hmac differs"))).
+		Add(5, "", nil)
+
+	err := batch.Err()
+	assert.Error(t, err)
+	assert.True(t, Is(err, ErrPCMForwarding))
+	assert.True(t, Is(err, ErrRequestParsing))
+	assert.True(t, Is(err, ErrSignatureMismatch))
+	assert.False(t, Is(err, ErrJWTEmptyToken))
+
+	items := batch.Items()
+	assert.Len(t, items, 2)
+	assert.Equal(t, 2, items[0].Index)
+	assert.Equal(t, "r-7", items[1].ID)
+
+	var item *ItemError
+	assert.True(t, As(err, &item))
+	assert.Equal(t, 2, item.Index)
+
+	assert.Equal(t, "2 of 10 items failed", batch.Summary())
+	assert.Contains(t, err.Error(), "item 7 (id r-7): error parsing request")
+}
+
+// TestBatchErrorRendering verifies the JSON and log representations of a
+// [*BatchError].
+func TestBatchErrorRendering(t *testing.T) {
+	batch := NewBatchError(ErrPCMForwarding, 5).
+		Add(1, "r-1", ErrRequestParsing).
+		Add(3, "r-3", ErrRequestParsing).
+		Add(2, "", fmt.Errorf("boom")).
+		Add(4, "r-4", Wrap(ErrObjectStorageDownload, fmt.Errorf("bucket internal-reports: permission denied")))
+
+	b, err := json.Marshal(batch)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"code": "pcm_forwarding",
+		"message": "error forwarding reports to pcm",
+		"total": 5,
+		"failed": 4,
+		"items": [
+			{"index": 1, "id": "r-1", "code": "request_parsing", "message": "error parsing request"},
+			{"index": 2, "message": "Internal Server Error"},
+			{"index": 3, "id": "r-3", "code": "request_parsing", "message": "error parsing request"},
+			{"index": 4, "id": "r-4", "code": "object_storage_download", "message": "`+ErrObjectStorageDownload.Error()+`"}
+		]
+	}`, string(b))
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+	logger.Warn().EmbedObject(batch).Msg(batch.Summary())
+
+	assert.JSONEq(t, `{
+		"level": "warn",
+		"total": 5,
+		"failed": 4,
+		"codes": {"object_storage_download": 1, "request_parsing": 2, "unknown": 1},
+		"message": "4 of 5 items failed"
+	}`, buf.String())
+}
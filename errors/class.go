@@ -0,0 +1,231 @@
+package errors
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/status"
+)
+
+// Class categorizes an [error] according to how the caller should react to
+// it, in particular whether the failed operation is worth retrying.
+type Class int
+
+const (
+	// ClassUnknown is reported for errors that carry no classification and
+	// cannot be classified from their cause.
+	ClassUnknown Class = iota
+
+	// ClassTransient marks failures that are expected to resolve on their own,
+	// such as dropped connections or temporary upstream outages.
+	ClassTransient
+
+	// ClassPermanent marks failures that will occur again if the operation is
+	// repeated unchanged, such as malformed requests or missing permissions.
+	ClassPermanent
+
+	// ClassRateLimited marks failures caused by the remote side throttling the
+	// caller. The operation may be retried after backing off.
+	ClassRateLimited
+
+	// ClassTimeout marks failures caused by an operation exceeding its deadline.
+	ClassTimeout
+)
+
+// String returns the lower-case name of the [Class].
+func (c Class) String() string {
+	switch c {
+	case ClassUnknown:
+		return "unknown"
+	case ClassTransient:
+		return "transient"
+	case ClassPermanent:
+		return "permanent"
+	case ClassRateLimited:
+		return "rate_limited"
+	case ClassTimeout:
+		return "timeout"
+	}
+
+	return "unknown"
+}
+
+// classified wraps an [error] with an explicit [Class], taking precedence over
+// any classification derived from the wrapped error.
+type classified struct {
+	err   error
+	class Class
+}
+
+// Error returns the message of the wrapped [error].
+func (c *classified) Error() string {
+	return c.err.Error()
+}
+
+// Unwrap exposes the wrapped [error] to [Is] and [As].
+func (c *classified) Unwrap() error {
+	return c.err
+}
+
+// Classify marks err with the given [Class]. It returns nil if err is nil.
+func Classify(err error, class Class) error {
+	if err == nil {
+		return nil
+	}
+
+	return &classified{err: err, class: class}
+}
+
+// Transient marks err as a transient failure that is worth retrying.
+func Transient(err error) error {
+	return Classify(err, ClassTransient)
+}
+
+// Permanent marks err as a permanent failure that must not be retried.
+func Permanent(err error) error {
+	return Classify(err, ClassPermanent)
+}
+
+// RateLimited marks err as a failure caused by remote throttling.
+func RateLimited(err error) error {
+	return Classify(err, ClassRateLimited)
+}
+
+// Timeout marks err as a failure caused by an exceeded deadline.
+func Timeout(err error) error {
+	return Classify(err, ClassTimeout)
+}
+
+// WithClass records the [Class] of the error, overriding the default class of
+// its sentinel. It returns the same [*Error] to enable method chaining.
+func (e *Error) WithClass(class Class) *Error {
+	e.class = class
+
+	return e
+}
+
+// ClassOf determines the [Class] of err. Explicit classifications made with
+// [Classify] or [Error.WithClass] take precedence, followed by well-known
+// causes found in the chain ([context.DeadlineExceeded], network timeouts,
+// connection-level system errors, [*googleapi.Error] codes, HTTP statuses
+// reported by [FromResponse] and gRPC status codes), and finally the default
+// class registered for the first sentinel in the chain.
+func ClassOf(err error) Class {
+	class := ClassUnknown
+
+	if walk(err, func(err error) bool {
+		switch x := err.(type) {
+		case *classified:
+			class = x.class
+		case *Error:
+			class = x.class
+		}
+		return class != ClassUnknown
+	}) {
+		return class
+	}
+
+	if walk(err, func(err error) bool {
+		class = classOfCause(err)
+		return class != ClassUnknown
+	}) {
+		return class
+	}
+
+	if def, ok := DefinitionOf(err); ok {
+		return def.Class
+	}
+
+	return ClassUnknown
+}
+
+// IsRetryable reports whether the operation that produced err is worth
+// retrying, which is the case for transient, rate-limited and timed-out
+// failures.
+func IsRetryable(err error) bool {
+	switch ClassOf(err) {
+	case ClassTransient, ClassRateLimited, ClassTimeout:
+		return true
+	case ClassUnknown, ClassPermanent:
+		return false
+	}
+
+	return false
+}
+
+// ClassOfStatus returns the [Class] of a failed HTTP response with the given
+// status code: 429 is rate-limited, 408 and 504 are timeouts, other 5xx codes
+// are transient and remaining 4xx codes are permanent.
+func ClassOfStatus(status int) Class {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return ClassRateLimited
+	case status == http.StatusRequestTimeout, status == http.StatusGatewayTimeout:
+		return ClassTimeout
+	case status >= http.StatusInternalServerError:
+		return ClassTransient
+	case status >= http.StatusBadRequest:
+		return ClassPermanent
+	}
+
+	return ClassUnknown
+}
+
+// classOfCause classifies a single [error] of a well-known type, without
+// inspecting its chain.
+func classOfCause(err error) Class {
+	if err == context.DeadlineExceeded { //nolint:errorlint // the chain is walked by the caller
+		return ClassTimeout
+	}
+
+	if err == io.ErrUnexpectedEOF { //nolint:errorlint // the chain is walked by the caller
+		return ClassTransient
+	}
+
+	if errno, ok := err.(syscall.Errno); ok { //nolint:errorlint // the chain is walked by the caller
+		return classOfErrno(errno)
+	}
+
+	switch x := err.(type) {
+	case *Error, *classified:
+		return ClassUnknown
+	case *googleapi.Error:
+		return ClassOfStatus(x.Code)
+	case *net.DNSError:
+		switch {
+		case x.IsTimeout:
+			return ClassTimeout
+		case x.IsTemporary:
+			return ClassTransient
+		}
+	case net.Error:
+		// Other network errors, such as *url.Error or *net.OpError, are only
+		// transient when their cause is, as in a refused connection and
+		// unlike a failed TLS verification or an unsupported scheme
+		if x.Timeout() {
+			return ClassTimeout
+		}
+	case interface{ GRPCStatus() *status.Status }:
+		return classOfGRPCCode(x.GRPCStatus().Code())
+	}
+
+	return ClassUnknown
+}
+
+// classOfErrno classifies the connection-level system errors that are
+// expected to resolve on their own as transient.
+func classOfErrno(errno syscall.Errno) Class {
+	switch errno {
+	case syscall.ECONNREFUSED, syscall.ECONNRESET, syscall.ECONNABORTED, syscall.EPIPE,
+		syscall.ENETUNREACH, syscall.EHOSTUNREACH, syscall.ENETDOWN:
+		return ClassTransient
+	case syscall.ETIMEDOUT:
+		return ClassTimeout
+	}
+
+	return ClassUnknown
+}
@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+)
+
+// TestClassOf verifies that errors are classified from explicit marks, from
+// well-known causes and from the defaults registered for their sentinels, in
+// that order of precedence.
+func TestClassOf(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		class     Class
+		retryable bool
+	}{
+		{"nil", nil, ClassUnknown, false},
+		{"unknown", fmt.Errorf("boom"), ClassUnknown, false},
+		{"sentinel default", Wrap(ErrPCMForwarding, fmt.Errorf("boom")), ClassTransient, true},
+		{"permanent sentinel", ErrRequestParsing, ClassPermanent, false},
+		{"deadline", Wrap(ErrObjectStorageUpload, context.DeadlineExceeded), ClassTimeout, true},
+		{"net timeout", &net.OpError{Op: "dial", Err: &net.DNSError{IsTimeout: true}}, ClassTimeout, true},
+		{"dns temporary", &net.OpError{Op: "dial", Err: &net.DNSError{IsTemporary: true}}, ClassTransient, true},
+		{"dns not found", &net.OpError{Op: "dial", Err: &net.DNSError{IsNotFound: true}}, ClassUnknown, false},
+		{"connection refused", &url.Error{Op: "Get", Err: &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}}, ClassTransient, true},
+		{"tls verification", &url.Error{Op: "Get", Err: &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}}, ClassUnknown, false},
+		{"unsupported scheme", &url.Error{Op: "Get", Err: fmt.Errorf("unsupported protocol scheme %q", "ftp")}, ClassUnknown, false},
+		{"gcs rate limit", Wrap(ErrObjectStorageUpload, &googleapi.Error{Code: http.StatusTooManyRequests}), ClassRateLimited, true},
+		{"gcs forbidden", Wrap(ErrObjectStorageUpload, &googleapi.Error{Code: http.StatusForbidden}), ClassPermanent, false},
+		{"gcs unavailable", Wrap(ErrObjectStorageDownload, &googleapi.Error{Code: http.StatusServiceUnavailable}), ClassTransient, true},
+		{"explicit mark", Permanent(Wrap(ErrPCMForwarding, context.DeadlineExceeded)), ClassPermanent, false},
+		{"explicit class", NewError(ErrInvalidEnv).WithClass(ClassTransient), ClassTransient, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.class, ClassOf(tt.err))
+			assert.Equal(t, tt.retryable, IsRetryable(tt.err))
+		})
+	}
+}
+
+// TestClassOfStatus verifies the classification of HTTP status codes,
+// including responses decoded by [FromResponse].
+func TestClassOfStatus(t *testing.T) {
+	assert.Equal(t, ClassRateLimited, ClassOfStatus(http.StatusTooManyRequests))
+	assert.Equal(t, ClassTimeout, ClassOfStatus(http.StatusGatewayTimeout))
+	assert.Equal(t, ClassTransient, ClassOfStatus(http.StatusBadGateway))
+	assert.Equal(t, ClassPermanent, ClassOfStatus(http.StatusUnauthorized))
+	assert.Equal(t, ClassUnknown, ClassOfStatus(http.StatusOK))
+
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	assert.True(t, IsRetryable(FromResponse(resp)))
+
+	resp = &http.Response{StatusCode: http.StatusBadRequest, Header: http.Header{}}
+	assert.False(t, IsRetryable(FromResponse(resp)))
+}
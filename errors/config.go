@@ -19,3 +19,26 @@ var ErrInvalidPath = fmt.Errorf("invalid path")
 // application configuration or execution cannot proceed without this variable
 // being set.
 var ErrMissingEnv = fmt.Errorf("missing environment variable")
+
+// ErrInvalidBinding represents the error condition where a struct cannot be
+// bound to environment variables, because it is not a pointer to a struct or
+// one of its tagged fields has a type that cannot be read from the
+// environment. This indicates a programming error rather than a deployment
+// problem.
+var ErrInvalidBinding = fmt.Errorf("invalid configuration binding")
+
+// ErrInvalidConfigFile represents an error that occurs when the configuration
+// file selected through the environment cannot be read or parsed, or holds
+// values that cannot be used as environment variables.
+var ErrInvalidConfigFile = fmt.Errorf("invalid configuration file")
+
+// ErrInvalidSecretFile represents an error that occurs when a secret is read
+// from a file, through the _FILE variant of an environment variable, and the
+// file cannot be read or has permissions that would let other users tamper
+// with the secret.
+var ErrInvalidSecretFile = fmt.Errorf("invalid secret file")
+
+// ErrConflictingEnv represents an error that occurs when an environment
+// variable and one of its deprecated aliases are both set, to different
+// values, so that the intended value cannot be known.
+var ErrConflictingEnv = fmt.Errorf("conflicting environment variables")
@@ -0,0 +1,369 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Code is a stable, machine-readable identifier for a class of failure. Unlike
+// the human-readable message of an [error], a Code is safe to compare, store
+// and transmit between services.
+type Code string
+
+// Redacted replaces the value of sensitive fields whenever an [*Error] is
+// rendered.
+const Redacted = "[REDACTED]"
+
+// Field is a single key/value pair of context attached to an [*Error]. The
+// value keeps its original Go type so that callers can retrieve it without
+// parsing the rendered message. Sensitive fields, such as tokens or signing
+// keys, are masked with [Redacted] by every rendering of the error.
+type Field struct {
+	Key       string
+	Value     any
+	Sensitive bool
+}
+
+// display returns the value of the field as it may be rendered, masking it if
+// the field is sensitive.
+func (f Field) display() any {
+	if f.Sensitive {
+		return Redacted
+	}
+
+	return f.Value
+}
+
+// Error is a structured [error] that carries a sentinel describing what went
+// wrong, a stable [Code], the failure source (one of [consts.PCM], [consts.SE]
+// or [consts.PARTICIPANT]), typed key/value context and the underlying cause.
+// It participates in [Is] and [As] through both its sentinel and its cause, so
+// existing checks such as Is(err, ErrInvalidEnv) keep working.
+type Error struct {
+	sentinel error
+	code     Code
+	source   string
+	fields   []Field
+	cause    error
+	class    Class
+	stack    Stack
+}
+
+// NewError creates a new [*Error] for the given sentinel. The sentinel provides
+// the message and the default [Code] of the error, and is matched by [Is].
+// Further context is attached by chaining the With methods. The call stack is
+// recorded according to the active [StackMode].
+func NewError(sentinel error) *Error {
+	return &Error{
+		sentinel: sentinel,
+		stack:    callers(1),
+	}
+}
+
+// WithCode overrides the [Code] that would otherwise be derived from the
+// sentinel registry. It returns the same [*Error] to enable method chaining.
+func (e *Error) WithCode(code Code) *Error {
+	e.code = code
+
+	return e
+}
+
+// WithSource records the component responsible for the failure, typically one
+// of [consts.PCM], [consts.SE] or [consts.PARTICIPANT]. It returns the same
+// [*Error] to enable method chaining.
+func (e *Error) WithSource(source string) *Error {
+	e.source = source
+
+	return e
+}
+
+// WithField attaches a key/value pair of context to the error. Setting a key
+// that is already present replaces its value. It returns the same [*Error] to
+// enable method chaining.
+func (e *Error) WithField(key string, value any) *Error {
+	return e.setField(Field{Key: key, Value: value})
+}
+
+// WithSensitiveField attaches a key/value pair of context whose value must
+// never be rendered, such as a token or a signing key. The value remains
+// available through [Error.Field] and [Lookup], but is masked with [Redacted]
+// by [Error.Error], [Error.MarshalJSON] and problem responses. Any occurrence
+// of a sensitive string value in the message of the cause is masked as well.
+// It returns the same [*Error] to enable method chaining.
+func (e *Error) WithSensitiveField(key string, value any) *Error {
+	return e.setField(Field{Key: key, Value: value, Sensitive: true})
+}
+
+// setField adds f to the fields of the error, replacing any field with the
+// same key.
+func (e *Error) setField(f Field) *Error {
+	for i := range e.fields {
+		if e.fields[i].Key == f.Key {
+			e.fields[i] = f
+			return e
+		}
+	}
+
+	e.fields = append(e.fields, f)
+
+	return e
+}
+
+// WithCause records the underlying [error] that triggered this failure. The
+// cause is part of the error chain and is therefore visible to [Is] and [As].
+// It returns the same [*Error] to enable method chaining.
+func (e *Error) WithCause(err error) *Error {
+	e.cause = err
+
+	return e
+}
+
+// Sentinel returns the sentinel [error] this error was created from.
+func (e *Error) Sentinel() error {
+	return e.sentinel
+}
+
+// Code returns the [Code] of the error. If no code was set explicitly, the
+// code registered for the sentinel is returned, or an empty [Code] if the
+// sentinel is not registered.
+func (e *Error) Code() Code {
+	if e.code != "" {
+		return e.code
+	}
+
+	if def, ok := lookupDefinition(e.sentinel); ok {
+		return def.Code
+	}
+
+	return ""
+}
+
+// Source returns the failure source recorded with [Error.WithSource], or an
+// empty string if none was set.
+func (e *Error) Source() string {
+	return e.source
+}
+
+// Fields returns a copy of the key/value context attached to the error, in the
+// order it was added.
+func (e *Error) Fields() []Field {
+	fields := make([]Field, len(e.fields))
+	copy(fields, e.fields)
+
+	return fields
+}
+
+// Field retrieves the value stored under key. The boolean result reports
+// whether the key was present.
+func (e *Error) Field(key string) (any, bool) {
+	for _, f := range e.fields {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// Cause returns the underlying [error] recorded with [Error.WithCause].
+func (e *Error) Cause() error {
+	return e.cause
+}
+
+// Error renders the sentinel message followed by the attached fields and the
+// cause, masking sensitive fields, for example "invalid environment variable
+// env=SE_GA_PORT value=abc: strconv.Atoi: parsing "abc": invalid syntax".
+func (e *Error) Error() string {
+	var b strings.Builder
+
+	if e.sentinel != nil {
+		b.WriteString(e.sentinel.Error())
+	}
+
+	for _, f := range e.fields {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		_, _ = fmt.Fprintf(&b, "%s=%v", f.Key, f.display())
+	}
+
+	if e.cause != nil {
+		if b.Len() > 0 {
+			b.WriteString(": ")
+		}
+		b.WriteString(e.redact(e.cause.Error()))
+	}
+
+	return b.String()
+}
+
+// redact masks every occurrence of the string value of a sensitive field in
+// msg, so that causes that quote their input, such as parsing errors, do not
+// leak it.
+func (e *Error) redact(msg string) string {
+	for _, f := range e.fields {
+		if !f.Sensitive {
+			continue
+		}
+
+		if v := fmt.Sprint(f.Value); v != "" {
+			msg = strings.ReplaceAll(msg, v, Redacted)
+		}
+	}
+
+	return msg
+}
+
+// Format implements [fmt.Formatter]. The %+v verb appends the stack recorded
+// at creation to the message; every other verb renders the message only.
+func (e *Error) Format(st fmt.State, verb rune) {
+	formatError(st, verb, e.Error(), e.stack)
+}
+
+// Stack returns the call stack recorded when the error was created, or nil if
+// stack capture was disabled.
+func (e *Error) Stack() Stack {
+	return e.stack
+}
+
+// Unwrap exposes the sentinel and the cause to [Is] and [As].
+func (e *Error) Unwrap() []error {
+	errl := make([]error, 0, 2)
+
+	if e.sentinel != nil {
+		errl = append(errl, e.sentinel)
+	}
+
+	if e.cause != nil {
+		errl = append(errl, e.cause)
+	}
+
+	return errl
+}
+
+// MarshalJSON encodes the error as a JSON object containing its code, source,
+// message, fields and cause message.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	type jsonError struct {
+		Code    Code           `json:"code,omitempty"`
+		Source  string         `json:"source,omitempty"`
+		Message string         `json:"message"`
+		Fields  map[string]any `json:"fields,omitempty"`
+		Cause   string         `json:"cause,omitempty"`
+	}
+
+	je := jsonError{
+		Code:   e.Code(),
+		Source: e.source,
+	}
+
+	if e.sentinel != nil {
+		je.Message = e.sentinel.Error()
+	}
+
+	if len(e.fields) > 0 {
+		je.Fields = make(map[string]any, len(e.fields))
+		for _, f := range e.fields {
+			je.Fields[f.Key] = f.display()
+		}
+	}
+
+	if e.cause != nil {
+		je.Cause = e.redact(e.cause.Error())
+	}
+
+	return json.Marshal(je)
+}
+
+// CodeOf returns the [Code] of the first [*Error] or registered sentinel found
+// in err's chain. It returns an empty [Code] if none is found.
+func CodeOf(err error) Code {
+	var code Code
+
+	walk(err, func(err error) bool {
+		if e, ok := err.(*Error); ok {
+			code = e.Code()
+			return code != ""
+		}
+
+		if def, ok := lookupDefinition(err); ok {
+			code = def.Code
+			return true
+		}
+
+		return false
+	})
+
+	return code
+}
+
+// SourceOf returns the failure source of the first [*Error] in err's chain
+// that has one. If none was recorded explicitly, the default source of the
+// first registered sentinel is returned, or an empty string if there is none.
+func SourceOf(err error) string {
+	var source string
+
+	walk(err, func(err error) bool {
+		if e, ok := err.(*Error); ok && e.source != "" {
+			source = e.source
+			return true
+		}
+
+		return false
+	})
+
+	if source == "" {
+		if def, ok := DefinitionOf(err); ok {
+			source = def.Source
+		}
+	}
+
+	return source
+}
+
+// Lookup retrieves the value stored under key by the first [*Error] in err's
+// chain that holds it, converted to T. The boolean result reports whether a
+// value of type T was found.
+func Lookup[T any](err error, key string) (T, bool) {
+	var value T
+	var found bool
+
+	walk(err, func(err error) bool {
+		if e, ok := err.(*Error); ok {
+			if v, ok := e.Field(key); ok {
+				value, found = v.(T)
+				return true
+			}
+		}
+
+		return false
+	})
+
+	return value, found
+}
+
+// walk visits err and every [error] in its chain depth-first, in the same
+// order used by [Is]. It stops as soon as fn returns true.
+func walk(err error, fn func(error) bool) bool {
+	if err == nil {
+		return false
+	}
+
+	if fn(err) {
+		return true
+	}
+
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		return walk(x.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, err := range x.Unwrap() {
+			if walk(err, fn) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,107 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stellarentropy/gravity-assist-common/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestErrorMatchesSentinel verifies that an [*Error] is matched by [Is] against
+// both its sentinel and its cause, and can be extracted with [As] from a
+// wrapped chain.
+func TestErrorMatchesSentinel(t *testing.T) {
+	cause := fmt.Errorf("connection reset")
+	err := Wrap(ErrObjectStorageUpload, NewError(ErrInvalidEnv).WithCause(cause))
+
+	assert.True(t, Is(err, ErrInvalidEnv))
+	assert.True(t, Is(err, ErrObjectStorageUpload))
+	assert.True(t, Is(err, cause))
+	assert.False(t, Is(err, ErrMissingEnv))
+
+	var e *Error
+	assert.True(t, As(err, &e))
+	assert.Equal(t, ErrInvalidEnv, e.Sentinel())
+}
+
+// TestErrorContext verifies that the code, source and typed fields of an
+// [*Error] can be retrieved without parsing its message.
+func TestErrorContext(t *testing.T) {
+	err := NewError(ErrInvalidEnv).
+		WithSource(consts.SE).
+		WithField("env", "SE_GA_HEALTH_LISTEN_PORT").
+		WithField("max", 65535)
+
+	assert.Equal(t, Code("invalid_env"), err.Code())
+	assert.Equal(t, Code("invalid_env"), CodeOf(fmt.Errorf("loading config: %w", err)))
+	assert.Equal(t, consts.SE, SourceOf(err))
+
+	env, ok := Lookup[string](err, "env")
+	assert.True(t, ok)
+	assert.Equal(t, "SE_GA_HEALTH_LISTEN_PORT", env)
+
+	max, ok := Lookup[int](err, "max")
+	assert.True(t, ok)
+	assert.Equal(t, 65535, max)
+
+	_, ok = Lookup[string](err, "max")
+	assert.False(t, ok)
+
+	assert.Equal(t, Code("custom"), err.WithCode("custom").Code())
+}
+
+// TestErrorRendering verifies the message and JSON representations of an
+// [*Error].
+func TestErrorRendering(t *testing.T) {
+	err := NewError(ErrInvalidEnv).
+		WithSource(consts.SE).
+		WithField("env", "SE_GA_LOG_FORMAT").
+		WithField("value", "xml").
+		WithCause(fmt.Errorf("unsupported"))
+
+	assert.Equal(t, "invalid environment variable env=SE_GA_LOG_FORMAT value=xml: unsupported", err.Error())
+
+	b, jerr := json.Marshal(err)
+	assert.NoError(t, jerr)
+	assert.JSONEq(t, `{
+		"code": "invalid_env",
+		"source": "SE",
+		"message": "invalid environment variable",
+		"fields": {"env": "SE_GA_LOG_FORMAT", "value": "xml"},
+		"cause": "unsupported"
+	}`, string(b))
+}
+
+// TestErrorRedaction verifies that the value of a sensitive field never
+// reaches the message, the JSON representation or a problem response, even
+// when the cause quotes it, while remaining available programmatically.
+func TestErrorRedaction(t *testing.T) {
+	secret := "s3cr3t-signing-key"
+
+	err := NewError(ErrInvalidEnv).
+		WithField("env", "SE_GA_SIGNING_KEY").
+		WithSensitiveField("value", secret).
+		WithCause(fmt.Errorf("parsing %q: invalid syntax", secret))
+
+	b, jerr := json.Marshal(err)
+	assert.NoError(t, jerr)
+
+	for _, out := range []string{
+		err.Error(),
+		fmt.Sprintf("%+v", Wrap(ErrRequestParsing, err)),
+		string(b),
+	} {
+		assert.NotContains(t, out, secret)
+		assert.Contains(t, out, Redacted)
+	}
+
+	p := NewProblem(Wrap(ErrRequestParsing, err))
+	assert.NotContains(t, p.Detail, secret)
+	assert.Equal(t, Redacted, p.Fields["value"])
+
+	value, ok := Lookup[string](err, "value")
+	assert.True(t, ok)
+	assert.Equal(t, secret, value)
+}
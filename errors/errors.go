@@ -7,19 +7,21 @@ import (
 
 // New creates a new error with the specified message and returns an [error]. If
 // the message is empty, the returned error will have an empty message string.
+// The call stack is recorded according to the active [StackMode].
 func New(msg string, args ...interface{}) error {
-	return fmt.Errorf(msg, args...)
+	return attachStack(fmt.Errorf(msg, args...), 1)
 }
 
 // Wrap consolidates a sequence of [error]s into a single [error], streamlining
 // error handling by combining multiple errors into one. It returns nil if there
 // is no error to wrap, indicated by the last [error] in the sequence being nil.
+// The call stack is recorded according to the active [StackMode].
 func Wrap(errl ...error) error {
 	if errl[len(errl)-1] == nil {
 		return nil
 	}
 
-	return errs.Join(errl...)
+	return attachStack(errs.Join(errl...), 1)
 }
 
 // Is reports whether any [error] in err's chain matches target, considering
@@ -0,0 +1,225 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcErrorDomain identifies the [errdetails.ErrorInfo] details produced by
+// this package among the details of a gRPC status.
+const grpcErrorDomain = "gravity-assist"
+
+// grpcSourceKey is the metadata key of the [errdetails.ErrorInfo] detail that
+// carries the failure source.
+const grpcSourceKey = "source"
+
+// ToGRPCStatus converts err into a gRPC [*status.Status]. The gRPC code is the
+// one registered for the first sentinel in err's chain or, when none is
+// registered, the code corresponding to its HTTP status. The status carries an
+// [errdetails.ErrorInfo] detail whose reason is the error [Code] and whose
+// metadata holds the failure source and, for client errors, the (redacted)
+// fields of the error. It returns nil if err is nil.
+func ToGRPCStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+
+	p := NewProblem(err)
+
+	var code codes.Code
+	if def, ok := DefinitionOf(err); ok && def.GRPCCode != codes.OK {
+		code = def.GRPCCode
+	} else {
+		code = grpcCodeOfStatus(p.Status)
+	}
+
+	switch {
+	case Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case Is(err, context.Canceled):
+		code = codes.Canceled
+	}
+
+	msg := p.Detail
+	if msg == "" {
+		msg = p.Title
+	}
+
+	info := &errdetails.ErrorInfo{
+		Reason:   string(p.Code),
+		Domain:   grpcErrorDomain,
+		Metadata: map[string]string{grpcSourceKey: p.Source},
+	}
+
+	for k, v := range p.Fields {
+		if k != grpcSourceKey {
+			info.Metadata[k] = fmt.Sprint(v)
+		}
+	}
+
+	st := status.New(code, msg)
+
+	if detailed, derr := st.WithDetails(info); derr == nil {
+		st = detailed
+	}
+
+	return st
+}
+
+// FromGRPCStatus rebuilds the [error] described by a gRPC [*status.Status],
+// typically one produced by [ToGRPCStatus] in another service. The [Code]
+// found in its [errdetails.ErrorInfo] detail is matched against the registry,
+// so that the returned [*Error] satisfies [Is] for the original sentinel;
+// statuses that cannot be matched yield [ErrRemoteFailure]. The failure source
+// and the metadata of the detail are restored as fields, along with the gRPC
+// code under "grpcCode". It returns nil for a nil or OK status.
+func FromGRPCStatus(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	var info *errdetails.ErrorInfo
+
+	for _, detail := range st.Details() {
+		if d, ok := detail.(*errdetails.ErrorInfo); ok && d.GetDomain() == grpcErrorDomain {
+			info = d
+			break
+		}
+	}
+
+	code := Code(info.GetReason())
+
+	sentinel, ok := sentinelOf(code)
+	if !ok {
+		sentinel = ErrRemoteFailure
+	}
+
+	e := NewError(sentinel).
+		WithCode(code).
+		WithClass(classOfGRPCCode(st.Code())).
+		WithSource(info.GetMetadata()[grpcSourceKey])
+
+	metadata := info.GetMetadata()
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		if k != grpcSourceKey {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		e.WithField(k, metadata[k])
+	}
+
+	e.WithField("grpcCode", st.Code().String())
+
+	if msg := st.Message(); msg != "" {
+		e.WithCause(New("%s", msg))
+	}
+
+	return e
+}
+
+// FromGRPC rebuilds the [error] returned by a gRPC call, see
+// [FromGRPCStatus]. Errors that do not carry a gRPC status are returned
+// unchanged.
+func FromGRPC(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	return FromGRPCStatus(st)
+}
+
+// GRPCStatus converts the error into a gRPC [*status.Status], allowing an
+// [*Error] to be returned directly from a gRPC handler.
+func (e *Error) GRPCStatus() *status.Status {
+	return ToGRPCStatus(e)
+}
+
+// UnaryServerInterceptor converts the errors returned by unary handlers into
+// gRPC statuses with [ToGRPCStatus], so that every service reports failures
+// the same way.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			if _, ok := err.(interface{ GRPCStatus() *status.Status }); ok {
+				return resp, err
+			}
+			return resp, ToGRPCStatus(err).Err()
+		}
+
+		return resp, nil
+	}
+}
+
+// UnaryClientInterceptor converts the statuses returned by unary calls back
+// into errors with [FromGRPC], so that callers can match them with [Is].
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return FromGRPC(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// grpcCodeOfStatus maps an HTTP status code onto the closest gRPC code.
+func grpcCodeOfStatus(status int) codes.Code {
+	switch status {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusInternalServerError:
+		return codes.Internal
+	}
+
+	return codes.Unknown
+}
+
+// classOfGRPCCode returns the [Class] of a failed call with the given gRPC
+// code.
+func classOfGRPCCode(code codes.Code) Class {
+	switch code {
+	case codes.OK:
+		return ClassUnknown
+	case codes.DeadlineExceeded:
+		return ClassTimeout
+	case codes.ResourceExhausted:
+		return ClassRateLimited
+	case codes.Unavailable, codes.Aborted, codes.Internal, codes.Unknown:
+		return ClassTransient
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.FailedPrecondition, codes.OutOfRange,
+		codes.Unimplemented, codes.DataLoss, codes.Unauthenticated:
+		return ClassPermanent
+	}
+
+	return ClassUnknown
+}
@@ -0,0 +1,118 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stellarentropy/gravity-assist-common/consts"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// failingHealthServer is a gRPC health service whose checks fail with the
+// error configured for the requested service name.
+type failingHealthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	errs map[string]error
+}
+
+// Check returns the error configured for the requested service.
+func (s *failingHealthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	return nil, s.errs[req.GetService()]
+}
+
+// newTestHealthClient starts an in-process gRPC server backed by
+// [failingHealthServer] and returns a client connected to it. Both use the
+// interceptors of this package.
+func newTestHealthClient(t *testing.T, errs map[string]error) grpc_health_v1.HealthClient {
+	listener := bufconn.Listen(1 << 20)
+
+	server := grpc.NewServer(grpc.UnaryInterceptor(UnaryServerInterceptor()))
+	grpc_health_v1.RegisterHealthServer(server, &failingHealthServer{errs: errs})
+
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+// TestGRPCRoundTrip verifies that errors returned by a gRPC handler reach the
+// client with the expected gRPC code and are rebuilt into errors matching the
+// original sentinel, failure source and code.
+func TestGRPCRoundTrip(t *testing.T) {
+	client := newTestHealthClient(t, map[string]error{
+		"signature": NewError(ErrSignatureMismatch).WithField("principal", "p-1"),
+		"pcm":       Wrap(ErrPCMForwarding, fmt.Errorf("connection refused")),
+		"deadline":  Wrap(ErrObjectStorageUpload, context.DeadlineExceeded),
+		"unknown":   fmt.Errorf("boom"),
+	})
+
+	tests := []struct {
+		service  string
+		sentinel error
+		grpcCode codes.Code
+		source   string
+		class    Class
+	}{
+		{"signature", ErrSignatureMismatch, codes.Unauthenticated, consts.PARTICIPANT, ClassPermanent},
+		{"pcm", ErrPCMForwarding, codes.Unavailable, consts.PCM, ClassTransient},
+		{"deadline", ErrObjectStorageUpload, codes.DeadlineExceeded, consts.SE, ClassTimeout},
+		{"unknown", ErrRemoteFailure, codes.Internal, consts.SE, ClassTransient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.service, func(t *testing.T) {
+			_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: tt.service})
+
+			assert.True(t, Is(err, tt.sentinel))
+			assert.Equal(t, tt.source, SourceOf(err))
+			assert.Equal(t, tt.class, ClassOf(err))
+
+			grpcCode, ok := Lookup[string](err, "grpcCode")
+			assert.True(t, ok)
+			assert.Equal(t, tt.grpcCode.String(), grpcCode)
+		})
+	}
+
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "signature"})
+	principal, ok := Lookup[string](err, "principal")
+	assert.True(t, ok)
+	assert.Equal(t, "p-1", principal)
+}
+
+// TestToGRPCStatus verifies the status produced for an error and that it
+// converts back into an equivalent error.
+func TestToGRPCStatus(t *testing.T) {
+	assert.Nil(t, ToGRPCStatus(nil))
+	assert.NoError(t, FromGRPCStatus(status.New(codes.OK, "")))
+
+	st := ToGRPCStatus(NewError(ErrMissingEnv).WithSource(consts.SE))
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+	assert.Equal(t, "missing environment variable", st.Message())
+
+	err := FromGRPC(st.Err())
+	assert.True(t, Is(err, ErrMissingEnv))
+	assert.Equal(t, Code("missing_env"), CodeOf(err))
+
+	plain := fmt.Errorf("not a grpc error")
+	assert.Equal(t, plain, FromGRPC(plain))
+}
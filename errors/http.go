@@ -21,3 +21,8 @@ var ErrResponseWriting = fmt.Errorf("error writing response")
 // request data, which may arise from network issues, improperly formatted
 // input, or a premature end of the data stream.
 var ErrRequestBodyReading = fmt.Errorf("error reading request body")
+
+// ErrRemoteFailure represents a failure reported by another service whose
+// response could not be matched to a known sentinel, either because it carried
+// no problem details or because its error code is not registered locally.
+var ErrRemoteFailure = fmt.Errorf("remote service failure")
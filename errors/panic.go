@@ -0,0 +1,150 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/rs/zerolog"
+)
+
+// ErrPanic represents a panic that was recovered and converted into an
+// [error], so that a failing goroutine or request handler is reported instead
+// of taking down the whole process.
+var ErrPanic = fmt.Errorf("recovered from panic")
+
+// FromPanic converts a value recovered from a panic into an [*Error] for the
+// given component. The error carries the component name and the panic value
+// as fields, the panic value as its cause when it is an [error], and the full
+// stack of the panicking goroutine regardless of the active [StackMode]. It
+// is meant to be called from a deferred function.
+func FromPanic(component string, value any) *Error {
+	e := &Error{
+		sentinel: ErrPanic,
+		stack:    captureStack(1, maxStackDepth),
+	}
+
+	e.WithField("component", component).
+		WithField("panic", fmt.Sprint(value))
+
+	if err, ok := value.(error); ok {
+		e.WithCause(err)
+	}
+
+	return e
+}
+
+// Recover recovers from a panic in the calling goroutine, logs it as an
+// [*Error] built by [FromPanic] and, when errp is not nil, stores the error in
+// it. The logger is expected to carry the component name in its context, as
+// the loggers of every component do. It must be deferred directly, for
+// example:
+//
+//	defer errors.Recover("health", logger.Logger, &err)
+//
+// The stack is written to the log event through zerolog.ErrorStackMarshaler,
+// which logging.GetLogger sets to [MarshalStack].
+func Recover(component string, logger zerolog.Logger, errp *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	err := FromPanic(component, r)
+
+	logger.Error().
+		Stack().
+		Err(err).
+		Msg("recovered from panic")
+
+	if errp != nil {
+		*errp = err
+	}
+}
+
+// Repanic recovers from a panic in the calling goroutine, logs it as an
+// [*Error] built by [FromPanic] as [Recover] does, and panics again with the
+// error. It is meant for failures that must still stop the process, such as
+// those of a component the service cannot run without, and must be deferred
+// directly.
+func Repanic(component string, logger zerolog.Logger) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	err := FromPanic(component, r)
+
+	logger.Error().
+		Stack().
+		Err(err).
+		Msg("recovered from panic")
+
+	panic(err)
+}
+
+// Go runs fn in a new goroutine on behalf of component. A panic in fn is
+// recovered and converted into an [*Error] by [FromPanic]. The error returned
+// by fn or recovered from its panic is logged with logger and sent to the
+// returned channel, which is closed once fn has completed. The channel is
+// buffered, so that callers are free to ignore it.
+func Go(component string, logger zerolog.Logger, fn func() error) <-chan error {
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+
+		var err error
+
+		func() {
+			defer Recover(component, logger, &err)
+
+			if err = fn(); err != nil {
+				logger.Error().
+					Stack().
+					Err(err).
+					Msg("component failed")
+			}
+		}()
+
+		if err != nil {
+			errc <- err
+		}
+	}()
+
+	return errc
+}
+
+// Recoverer returns a chi middleware that recovers from panics in downstream
+// handlers, logs them through [Recover] and answers with an internal server
+// error problem response written by [WriteProblem]. Panics with
+// [http.ErrAbortHandler] are re-raised, as they are used by net/http to abort
+// a response on purpose.
+func Recoverer(component string, logger zerolog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+
+				if rec == http.ErrAbortHandler { //nolint:errorlint // net/http panics with the sentinel itself
+					panic(rec)
+				}
+
+				err := FromPanic(component, rec).
+					WithField("method", r.Method).
+					WithField("path", r.URL.Path)
+
+				logger.Error().
+					Stack().
+					Err(err).
+					Msg("recovered from panic")
+
+				_ = WriteProblem(w, r, err)
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// logEntry captures the fields written by the recovery helpers.
+type logEntry struct {
+	Component string  `json:"component"`
+	Error     string  `json:"error"`
+	Message   string  `json:"message"`
+	Stack     []Frame `json:"stack"`
+}
+
+// newTestLogger returns a component logger writing JSON to buf, with stacks
+// marshaled by [MarshalStack] as configured by logging.GetLogger.
+func newTestLogger(t *testing.T, buf *bytes.Buffer, component string) zerolog.Logger {
+	marshaler := zerolog.ErrorStackMarshaler
+	zerolog.ErrorStackMarshaler = MarshalStack
+	t.Cleanup(func() { zerolog.ErrorStackMarshaler = marshaler })
+
+	return zerolog.New(buf).With().Str("component", component).Logger()
+}
+
+// TestRecover verifies that a recovered panic is logged with its component,
+// value and stack, and returned as an [*Error] matching [ErrPanic] and the
+// panic value.
+func TestRecover(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestLogger(t, &buf, "health")
+	cause := fmt.Errorf("listener closed")
+
+	run := func() (err error) {
+		defer Recover("health", logger, &err)
+		panic(cause)
+	}
+
+	err := run()
+	assert.True(t, Is(err, ErrPanic))
+	assert.True(t, Is(err, cause))
+	assert.NotNil(t, StackOf(err))
+
+	component, ok := Lookup[string](err, "component")
+	assert.True(t, ok)
+	assert.Equal(t, "health", component)
+
+	var entry logEntry
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "health", entry.Component)
+	assert.Equal(t, "recovered from panic", entry.Message)
+	assert.Contains(t, entry.Error, "listener closed")
+	assert.NotEmpty(t, entry.Stack)
+}
+
+// TestRepanic verifies that a panic is logged as [Recover] does, then raised
+// again as an [*Error] matching [ErrPanic] and the panic value.
+func TestRepanic(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestLogger(t, &buf, "tracer")
+	cause := fmt.Errorf("exporter unavailable")
+
+	run := func() (err error) {
+		defer func() { err, _ = recover().(error) }()
+		defer Repanic("tracer", logger)
+		panic(cause)
+	}
+
+	err := run()
+	assert.True(t, Is(err, ErrPanic))
+	assert.True(t, Is(err, cause))
+
+	var entry logEntry
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "tracer", entry.Component)
+	assert.Contains(t, entry.Error, "exporter unavailable")
+	assert.NotEmpty(t, entry.Stack)
+}
+
+// TestGo verifies that [Go] reports both returned errors and panics through
+// its channel, and closes it once the function has completed.
+func TestGo(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestLogger(t, &buf, "tracer")
+
+	err := <-Go("tracer", logger, func() error { panic("exporter unavailable") })
+	assert.True(t, Is(err, ErrPanic))
+
+	value, ok := Lookup[string](err, "panic")
+	assert.True(t, ok)
+	assert.Equal(t, "exporter unavailable", value)
+
+	err = <-Go("tracer", logger, func() error { return ErrObjectStorageUpload })
+	assert.Equal(t, ErrObjectStorageUpload, err)
+
+	errc := Go("tracer", logger, func() error { return nil })
+	_, open := <-errc
+	assert.False(t, open)
+}
+
+// TestRecoverer verifies that the chi middleware turns a panicking handler
+// into an internal server error problem response.
+func TestRecoverer(t *testing.T) {
+	var buf bytes.Buffer
+
+	router := chi.NewRouter()
+	router.Use(Recoverer("api", newTestLogger(t, &buf, "api")))
+	router.Get("/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("nil map")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var p Problem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	assert.Equal(t, Code("panic"), p.Code)
+
+	var entry logEntry
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "api", entry.Component)
+}
@@ -0,0 +1,193 @@
+package errors
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+
+	"github.com/stellarentropy/gravity-assist-common/consts"
+)
+
+// problemTypePrefix is prepended to the [Code] of an error to build the "type"
+// member of a [Problem], producing a stable URI for each class of failure.
+const problemTypePrefix = "urn:gravity-assist:problem:"
+
+// maxProblemSize bounds the number of bytes read from a response body when
+// decoding a [Problem], protecting clients from unbounded remote payloads.
+const maxProblemSize = 1 << 20
+
+// Problem is the RFC 7807 "problem details" representation of an [error], as
+// rendered in application/problem+json responses. In addition to the standard
+// members it carries the error [Code], the failure source and the request id
+// so that clients can rebuild the original error.
+type Problem struct {
+	Type      string         `json:"type"`
+	Title     string         `json:"title"`
+	Status    int            `json:"status"`
+	Detail    string         `json:"detail,omitempty"`
+	Instance  string         `json:"instance,omitempty"`
+	Code      Code           `json:"code,omitempty"`
+	Source    string         `json:"source,omitempty"`
+	RequestId string         `json:"requestId,omitempty"`
+	Fields    map[string]any `json:"fields,omitempty"`
+}
+
+// NewProblem builds the [*Problem] describing err. The status, code, title
+// and default failure source come from the first registered sentinel in err's
+// chain; unknown errors are reported as internal server errors originating
+// from [consts.SE]. The detail and fields are only included for client
+// errors, so that server internals are never leaked to the caller, and
+// sensitive fields are always masked.
+func NewProblem(err error) *Problem {
+	p := &Problem{
+		Type:   "about:blank",
+		Status: http.StatusInternalServerError,
+		Code:   CodeOf(err),
+		Source: SourceOf(err),
+	}
+
+	if sentinel, def, ok := definitionOf(err); ok {
+		p.Title = sentinel.Error()
+		if def.Status != 0 {
+			p.Status = def.Status
+		}
+	}
+
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+
+	if p.Code != "" {
+		p.Type = problemTypePrefix + string(p.Code)
+	}
+
+	if p.Source == "" {
+		p.Source = consts.SE
+	}
+
+	if p.Status < http.StatusInternalServerError {
+		p.Detail = err.Error()
+
+		var e *Error
+		if As(err, &e) && len(e.fields) > 0 {
+			p.Fields = make(map[string]any, len(e.fields))
+			for _, f := range e.fields {
+				p.Fields[f.Key] = f.display()
+			}
+		}
+	}
+
+	return p
+}
+
+// WriteProblem renders err as an application/problem+json response. It sets
+// the status code of the response, the failure source header identified by
+// [consts.FailureSourceHeaderKey] and echoes the request id found in the
+// [consts.RequestIdHeaderKey] header of r, if any. An error is returned if the
+// body could not be written.
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) error {
+	p := NewProblem(err)
+
+	if r != nil {
+		p.Instance = r.URL.Path
+		p.RequestId = r.Header.Get(string(consts.RequestIdHeaderKey))
+	}
+
+	body, merr := json.Marshal(p)
+	if merr != nil {
+		return Wrap(ErrResponseWriting, merr)
+	}
+
+	w.Header().Set("Content-Type", consts.MIMEApplicationProblemJSON)
+	w.Header().Set(string(consts.FailureSourceHeaderKey), p.Source)
+
+	if p.RequestId != "" {
+		w.Header().Set(string(consts.RequestIdHeaderKey), p.RequestId)
+	}
+
+	w.WriteHeader(p.Status)
+
+	if _, werr := w.Write(body); werr != nil {
+		return Wrap(ErrResponseWriting, werr)
+	}
+
+	return nil
+}
+
+// FromResponse rebuilds the [error] reported by another Gravity Assist service
+// from its HTTP response. It returns nil for responses with a status code
+// below 400. Problem details are decoded from application/problem+json bodies
+// and the [Code] is matched against the registry, so that the returned
+// [*Error] satisfies [Is] for the sentinel raised by the remote service.
+// Responses that cannot be matched yield [ErrRemoteFailure]. The failure
+// source is taken from the [consts.FailureSourceHeaderKey] header, the status
+// code and request id are available as the "status" and "requestId" fields,
+// and the error is classified according to [ClassOfStatus]. The caller remains
+// responsible for closing the response body.
+func FromResponse(resp *http.Response) error {
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	p := &Problem{
+		Status:    resp.StatusCode,
+		RequestId: resp.Header.Get(string(consts.RequestIdHeaderKey)),
+	}
+
+	var cause error
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == consts.MIMEApplicationProblemJSON && resp.Body != nil {
+		decoded := &Problem{}
+		if err := json.NewDecoder(io.LimitReader(resp.Body, maxProblemSize)).Decode(decoded); err != nil {
+			cause = Wrap(ErrResponseParsing, err)
+		} else {
+			if decoded.RequestId == "" {
+				decoded.RequestId = p.RequestId
+			}
+			if decoded.Status == 0 {
+				decoded.Status = p.Status
+			}
+			p = decoded
+		}
+	}
+
+	sentinel, ok := sentinelOf(p.Code)
+	if !ok {
+		sentinel = ErrRemoteFailure
+	}
+
+	e := NewError(sentinel).
+		WithCode(p.Code).
+		WithClass(ClassOfStatus(resp.StatusCode))
+
+	if source := resp.Header.Get(string(consts.FailureSourceHeaderKey)); source != "" {
+		e.WithSource(source)
+	} else if p.Source != "" {
+		e.WithSource(p.Source)
+	}
+
+	keys := make([]string, 0, len(p.Fields))
+	for k := range p.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		e.WithField(k, p.Fields[k])
+	}
+
+	e.WithField("status", p.Status)
+
+	if p.RequestId != "" {
+		e.WithField("requestId", p.RequestId)
+	}
+
+	if cause == nil && p.Detail != "" {
+		cause = New("%s", p.Detail)
+	}
+
+	return e.WithCause(cause)
+}
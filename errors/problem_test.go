@@ -0,0 +1,117 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellarentropy/gravity-assist-common/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWriteProblem verifies that a client error is rendered as an RFC 7807
+// problem with the registered status, code and failure source, and that the
+// request id is echoed back.
+func TestWriteProblem(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/v1/reports", nil)
+	r.Header.Set(string(consts.RequestIdHeaderKey), "req-1")
+	w := httptest.NewRecorder()
+
+	err := Wrap(ErrSignatureMismatch, fmt.Errorf("hmac differs"))
+	assert.NoError(t, WriteProblem(w, r, err))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, consts.MIMEApplicationProblemJSON, w.Header().Get("Content-Type"))
+	assert.Equal(t, consts.PARTICIPANT, w.Header().Get(string(consts.FailureSourceHeaderKey)))
+	assert.Equal(t, "req-1", w.Header().Get(string(consts.RequestIdHeaderKey)))
+
+	var p Problem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	assert.Equal(t, "urn:gravity-assist:problem:signature_mismatch", p.Type)
+	assert.Equal(t, "signature mismatch", p.Title)
+	assert.Equal(t, http.StatusUnauthorized, p.Status)
+	assert.Equal(t, Code("signature_mismatch"), p.Code)
+	assert.Equal(t, "/v1/reports", p.Instance)
+	assert.Equal(t, "req-1", p.RequestId)
+	assert.NotEmpty(t, p.Detail)
+}
+
+// TestNewProblemServerError verifies that server errors do not expose their
+// detail, that an explicit failure source takes precedence over the registered
+// default and that unknown errors map to an internal server error.
+func TestNewProblemServerError(t *testing.T) {
+	p := NewProblem(NewError(ErrPCMForwarding).
+		WithSource(consts.SE).
+		WithField("host", "pcm.internal"))
+
+	assert.Equal(t, http.StatusBadGateway, p.Status)
+	assert.Equal(t, consts.SE, p.Source)
+	assert.Empty(t, p.Detail)
+	assert.Empty(t, p.Fields)
+
+	p = NewProblem(fmt.Errorf("boom"))
+
+	assert.Equal(t, http.StatusInternalServerError, p.Status)
+	assert.Equal(t, "about:blank", p.Type)
+	assert.Equal(t, http.StatusText(http.StatusInternalServerError), p.Title)
+	assert.Equal(t, consts.SE, p.Source)
+}
+
+// TestFromResponse verifies that a problem written by [WriteProblem] is
+// decoded back into an error matching the original sentinel, and that the
+// remote failure source and request id are preserved.
+func TestFromResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = WriteProblem(w, r, NewError(ErrSignatureMismatch).WithField("principal", "p-1"))
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+	req.Header.Set(string(consts.RequestIdHeaderKey), "req-2")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	rerr := FromResponse(resp)
+	assert.True(t, Is(rerr, ErrSignatureMismatch))
+	assert.Equal(t, consts.PARTICIPANT, SourceOf(rerr))
+	assert.Equal(t, Code("signature_mismatch"), CodeOf(rerr))
+
+	requestId, ok := Lookup[string](rerr, "requestId")
+	assert.True(t, ok)
+	assert.Equal(t, "req-2", requestId)
+
+	principal, ok := Lookup[string](rerr, "principal")
+	assert.True(t, ok)
+	assert.Equal(t, "p-1", principal)
+}
+
+// TestFromResponseUnknown verifies that successful responses decode to nil and
+// that failures without problem details map to [ErrRemoteFailure].
+func TestFromResponseUnknown(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.WriteHeader(http.StatusOK)
+
+	ok := w.Result()
+	defer func() { _ = ok.Body.Close() }()
+	assert.NoError(t, FromResponse(ok))
+
+	w = httptest.NewRecorder()
+	w.Header().Set(string(consts.FailureSourceHeaderKey), consts.PCM)
+	w.WriteHeader(http.StatusServiceUnavailable)
+
+	unavailable := w.Result()
+	defer func() { _ = unavailable.Body.Close() }()
+
+	err := FromResponse(unavailable)
+	assert.True(t, Is(err, ErrRemoteFailure))
+	assert.Equal(t, consts.PCM, SourceOf(err))
+
+	status, found := Lookup[int](err, "status")
+	assert.True(t, found)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+}
@@ -0,0 +1,141 @@
+package errors
+
+import (
+	"net/http"
+	"reflect"
+	"sync"
+
+	"github.com/stellarentropy/gravity-assist-common/consts"
+	"google.golang.org/grpc/codes"
+)
+
+// Definition describes the properties shared by every occurrence of a sentinel
+// [error]: the stable [Code] reported to clients, the HTTP status code used
+// when the error is returned from a handler, the default failure source used
+// when none was recorded with [Error.WithSource], the default [Class] used
+// when the error cannot be classified from its cause, and the gRPC code used
+// by [ToGRPCStatus]. A zero GRPCCode derives the gRPC code from Status.
+type Definition struct {
+	Code     Code
+	Status   int
+	Source   string
+	Class    Class
+	GRPCCode codes.Code
+}
+
+// registry holds the [Definition] of every known sentinel, keyed by the
+// sentinel itself.
+var registry = map[error]Definition{
+	ErrConflictingEnv:    {Code: "conflicting_env", Status: http.StatusInternalServerError, Source: consts.SE, Class: ClassPermanent, GRPCCode: codes.FailedPrecondition},
+	ErrInvalidBinding:    {Code: "invalid_binding", Status: http.StatusInternalServerError, Source: consts.SE, Class: ClassPermanent},
+	ErrInvalidConfigFile: {Code: "invalid_config_file", Status: http.StatusInternalServerError, Source: consts.SE, Class: ClassPermanent, GRPCCode: codes.FailedPrecondition},
+	ErrInvalidEnv:        {Code: "invalid_env", Status: http.StatusInternalServerError, Source: consts.SE, Class: ClassPermanent, GRPCCode: codes.FailedPrecondition},
+	ErrInvalidSecretFile: {Code: "invalid_secret_file", Status: http.StatusInternalServerError, Source: consts.SE, Class: ClassPermanent, GRPCCode: codes.FailedPrecondition},
+	ErrInvalidPath:       {Code: "invalid_path", Status: http.StatusInternalServerError, Source: consts.SE, Class: ClassPermanent},
+	ErrMissingEnv:        {Code: "missing_env", Status: http.StatusInternalServerError, Source: consts.SE, Class: ClassPermanent, GRPCCode: codes.FailedPrecondition},
+
+	ErrRequestHandling:    {Code: "request_handling", Status: http.StatusInternalServerError, Source: consts.SE, Class: ClassUnknown},
+	ErrResponseWriting:    {Code: "response_writing", Status: http.StatusInternalServerError, Source: consts.SE, Class: ClassTransient},
+	ErrRequestBodyReading: {Code: "request_body_reading", Status: http.StatusBadRequest, Source: consts.PARTICIPANT, Class: ClassPermanent},
+	ErrRemoteFailure:      {Code: "remote_failure", Status: http.StatusBadGateway, Source: consts.SE, Class: ClassTransient},
+	ErrPanic:              {Code: "panic", Status: http.StatusInternalServerError, Source: consts.SE, Class: ClassUnknown},
+
+	ErrJWTParsingFailed:  {Code: "jwt_parsing_failed", Status: http.StatusUnauthorized, Source: consts.PARTICIPANT, Class: ClassPermanent},
+	ErrJWTClaimNotFound:  {Code: "jwt_claim_not_found", Status: http.StatusUnauthorized, Source: consts.PARTICIPANT, Class: ClassPermanent},
+	ErrJWTEmptyToken:     {Code: "jwt_empty_token", Status: http.StatusUnauthorized, Source: consts.PARTICIPANT, Class: ClassPermanent},
+	ErrSignatureMismatch: {Code: "signature_mismatch", Status: http.StatusUnauthorized, Source: consts.PARTICIPANT, Class: ClassPermanent},
+	ErrInvalidSignature:  {Code: "invalid_signature", Status: http.StatusUnauthorized, Source: consts.PARTICIPANT, Class: ClassPermanent},
+
+	ErrPCMForwarding:   {Code: "pcm_forwarding", Status: http.StatusBadGateway, Source: consts.PCM, Class: ClassTransient},
+	ErrRequestParsing:  {Code: "request_parsing", Status: http.StatusBadRequest, Source: consts.PARTICIPANT, Class: ClassPermanent},
+	ErrResponseParsing: {Code: "response_parsing", Status: http.StatusBadGateway, Source: consts.PCM, Class: ClassPermanent},
+
+	ErrObjectStorageUpload:   {Code: "object_storage_upload", Status: http.StatusInternalServerError, Source: consts.SE, Class: ClassTransient},
+	ErrObjectStorageDownload: {Code: "object_storage_download", Status: http.StatusInternalServerError, Source: consts.SE, Class: ClassTransient},
+	ErrObjectStorageDelete:   {Code: "object_storage_delete", Status: http.StatusInternalServerError, Source: consts.SE, Class: ClassTransient},
+	ErrObjectNotFound:        {Code: "object_not_found", Status: http.StatusNotFound, Source: consts.SE, Class: ClassPermanent, GRPCCode: codes.NotFound},
+}
+
+var registryLock = sync.RWMutex{}
+
+// Register associates a [Definition] with a sentinel [error], allowing
+// services to declare their own sentinels alongside the ones provided by this
+// package. Registering a sentinel again replaces its previous [Definition].
+func Register(sentinel error, def Definition) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	registry[sentinel] = def
+}
+
+// DefinitionOf returns the [Definition] of the first registered sentinel found
+// in err's chain. The boolean result reports whether one was found.
+func DefinitionOf(err error) (Definition, bool) {
+	_, def, found := definitionOf(err)
+
+	return def, found
+}
+
+// StatusOf returns the HTTP status code registered for the first sentinel
+// found in err's chain, or [http.StatusInternalServerError] if err does not
+// match any registered sentinel.
+func StatusOf(err error) int {
+	if def, ok := DefinitionOf(err); ok && def.Status != 0 {
+		return def.Status
+	}
+
+	return http.StatusInternalServerError
+}
+
+// sentinelOf returns the registered sentinel whose [Definition] carries the
+// given [Code]. The boolean result reports whether one was found.
+func sentinelOf(code Code) (error, bool) {
+	if code == "" {
+		return nil, false
+	}
+
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	for sentinel, def := range registry {
+		if def.Code == code {
+			return sentinel, true
+		}
+	}
+
+	return nil, false
+}
+
+// definitionOf returns the first registered sentinel found in err's chain
+// together with its [Definition].
+func definitionOf(err error) (error, Definition, bool) {
+	var sentinel error
+	var def Definition
+	var found bool
+
+	walk(err, func(err error) bool {
+		def, found = lookupDefinition(err)
+		if found {
+			sentinel = err
+		}
+		return found
+	})
+
+	return sentinel, def, found
+}
+
+// lookupDefinition returns the [Definition] registered for exactly err,
+// without inspecting its chain. Errors whose dynamic type is not comparable
+// can never be sentinels and are skipped, as map lookups would panic on them.
+func lookupDefinition(err error) (Definition, bool) {
+	if err == nil || !reflect.TypeOf(err).Comparable() {
+		return Definition{}, false
+	}
+
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	def, ok := registry[err]
+
+	return def, ok
+}
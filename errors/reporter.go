@@ -0,0 +1,219 @@
+package errors
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// reportedErrorsMetric is the name of the counter incremented by a [Reporter]
+// with the number of occurrences of each fingerprint.
+const reportedErrorsMetric = "errors.reported"
+
+// DefaultReportWindow is the window of a [Reporter] created without a
+// positive one.
+const DefaultReportWindow = time.Minute
+
+// CounterFunc increments an integer counter. Its signature matches
+// tracer.AddInt64, which is the function normally given to [NewReporter].
+type CounterFunc func(ctx context.Context, component string, name string, value int64, opts ...metric.AddOption) error
+
+// reportEntry aggregates the occurrences of a single fingerprint within the
+// current window of a [Reporter].
+type reportEntry struct {
+	err   error
+	count int64
+	first time.Time
+	last  time.Time
+}
+
+// Reporter deduplicates reported errors by [Fingerprint] and periodically
+// emits a single summarized log line and a single counter increment for each
+// fingerprint seen during the window, instead of one entry per occurrence.
+// A Reporter is safe for concurrent use.
+type Reporter struct {
+	component string
+	window    time.Duration
+	logger    zerolog.Logger
+	counter   CounterFunc
+
+	lock    sync.Mutex
+	entries map[string]*reportEntry
+}
+
+// NewReporter creates a new [*Reporter] that aggregates errors over the given
+// window and reports them for component through logger and counter. A nil
+// counter disables metrics, and a window that is not positive is replaced by
+// [DefaultReportWindow].
+func NewReporter(component string, logger zerolog.Logger, counter CounterFunc, window time.Duration) *Reporter {
+	if window <= 0 {
+		window = DefaultReportWindow
+	}
+
+	return &Reporter{
+		component: component,
+		window:    window,
+		logger:    logger,
+		counter:   counter,
+		entries:   make(map[string]*reportEntry),
+	}
+}
+
+// Report records an occurrence of err. Only the first occurrence of each
+// fingerprint within a window is retained for logging. Nil errors are
+// ignored.
+func (r *Reporter) Report(err error) {
+	if err == nil {
+		return
+	}
+
+	fingerprint := Fingerprint(err)
+	now := time.Now()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	entry, ok := r.entries[fingerprint]
+	if !ok {
+		entry = &reportEntry{err: err, first: now}
+		r.entries[fingerprint] = entry
+	}
+
+	entry.count++
+	entry.last = now
+}
+
+// Flush emits the summary of every fingerprint recorded since the previous
+// flush and starts a new window.
+func (r *Reporter) Flush(ctx context.Context) {
+	r.lock.Lock()
+	entries := r.entries
+	r.entries = make(map[string]*reportEntry)
+	r.lock.Unlock()
+
+	fingerprints := make([]string, 0, len(entries))
+	for fingerprint := range entries {
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	sort.Strings(fingerprints)
+
+	for _, fingerprint := range fingerprints {
+		entry := entries[fingerprint]
+		code := string(CodeOf(entry.err))
+		source := SourceOf(entry.err)
+
+		r.logger.Error().
+			Err(entry.err).
+			Str("fingerprint", fingerprint).
+			Str("code", code).
+			Str("source", source).
+			Int64("count", entry.count).
+			Time("first", entry.first).
+			Time("last", entry.last).
+			Msg("error summary")
+
+		if r.counter != nil {
+			_ = r.counter(ctx, r.component, reportedErrorsMetric, entry.count,
+				metric.AddOption(metric.WithAttributes(
+					attribute.String("fingerprint", fingerprint),
+					attribute.String("code", code),
+					attribute.String("source", source),
+				)),
+			)
+		}
+	}
+}
+
+// Start flushes the [Reporter] at the end of every window until ctx is done,
+// then flushes it one last time. It follows the component convention of
+// registering itself with wg.
+func (r *Reporter) Start(ctx context.Context, wg *sync.WaitGroup) {
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(r.window)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.Flush(ctx)
+			case <-ctx.Done():
+				r.Flush(context.Background())
+				return
+			}
+		}
+	}()
+}
+
+// Fingerprint computes a stable identifier for err that groups together the
+// occurrences of the same failure. It is derived from the sentinels found in
+// the chain (or the types of its errors when none is registered), the [Code]
+// of the error and the frame where it originated, if a stack was recorded.
+// Messages are ignored when a sentinel is found, as they often contain
+// per-occurrence values such as ids or paths. Otherwise the messages of the
+// root causes are included once normalized by [normalizeMessage], so that
+// unrelated failures built with fmt.Errorf or errors.New are told apart.
+func Fingerprint(err error) string {
+	h := sha256.New()
+
+	var sentinels bool
+
+	walk(err, func(err error) bool {
+		if def, ok := lookupDefinition(err); ok {
+			sentinels = true
+			_, _ = h.Write([]byte("sentinel:" + string(def.Code) + "\n"))
+		}
+		return false
+	})
+
+	if !sentinels {
+		walk(err, func(err error) bool {
+			_, _ = h.Write([]byte("type:" + reflect.TypeOf(err).String() + "\n"))
+			if isRootCause(err) {
+				_, _ = h.Write([]byte("cause:" + normalizeMessage(err.Error()) + "\n"))
+			}
+			return false
+		})
+	}
+
+	_, _ = h.Write([]byte("code:" + string(CodeOf(err)) + "\n"))
+
+	if frames := StackOf(err).Frames(); len(frames) > 0 {
+		_, _ = h.Write([]byte("origin:" + frames[0].String() + "\n"))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// messageValues matches the quoted values and the numbers of an error
+// message, which usually differ between occurrences of the same failure.
+var messageValues = regexp.MustCompile("\"(?:[^\"\\\\]|\\\\.)*\"|'[^']*'|`[^`]*`|[0-9]+")
+
+// normalizeMessage strips the quoted values and the numbers of msg.
+func normalizeMessage(msg string) string {
+	return messageValues.ReplaceAllString(msg, "_")
+}
+
+// isRootCause reports whether err wraps no other error.
+func isRootCause(err error) bool {
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		return x.Unwrap() == nil
+	case interface{ Unwrap() []error }:
+		return len(x.Unwrap()) == 0
+	}
+
+	return true
+}
@@ -0,0 +1,92 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// TestFingerprint verifies that errors sharing a sentinel chain share a
+// fingerprint regardless of their messages, while different chains differ.
+func TestFingerprint(t *testing.T) {
+	a := Wrap(ErrObjectStorageDownload, fmt.Errorf("object a not found"))
+	b := Wrap(ErrObjectStorageDownload, fmt.Errorf("object b not found"))
+	c := Wrap(ErrObjectStorageUpload, fmt.Errorf("object a not found"))
+
+	assert.Equal(t, Fingerprint(a), Fingerprint(b))
+	assert.NotEqual(t, Fingerprint(a), Fingerprint(c))
+	assert.NotEqual(t, Fingerprint(fmt.Errorf("boom")), Fingerprint(a))
+
+	// Without a sentinel, root causes are told apart by their messages,
+	// stripped of their numbers and quoted values
+	timeout := fmt.Errorf("pcm: %w", fmt.Errorf("read %q: timeout after %dms", "/streams/12", 500))
+	assert.Equal(t, Fingerprint(timeout), Fingerprint(fmt.Errorf("pcm: %w", fmt.Errorf("read %q: timeout after %dms", "/streams/7", 250))))
+	assert.NotEqual(t, Fingerprint(timeout), Fingerprint(fmt.Errorf("pcm: %w", fmt.Errorf("read %q: connection reset", "/streams/12"))))
+	assert.NotEqual(t, Fingerprint(fmt.Errorf("disk full")), Fingerprint(fmt.Errorf("permission denied")))
+}
+
+// TestReporter verifies that repeated errors are summarized into a single log
+// line and a single counter increment per fingerprint.
+func TestReporter(t *testing.T) {
+	var buf bytes.Buffer
+	var increments []int64
+
+	counter := func(ctx context.Context, component string, name string, value int64, opts ...metric.AddOption) error {
+		assert.Equal(t, "test", component)
+		assert.Equal(t, "errors.reported", name)
+		increments = append(increments, value)
+		return nil
+	}
+
+	r := NewReporter("test", zerolog.New(&buf), counter, time.Minute)
+
+	for i := 0; i < 1000; i++ {
+		r.Report(Wrap(ErrObjectStorageDownload, fmt.Errorf("object %d not found", i)))
+	}
+	r.Report(ErrPCMForwarding)
+	r.Report(nil)
+
+	r.Flush(context.Background())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.ElementsMatch(t, []int64{1000, 1}, increments)
+
+	counts := map[string]int64{}
+	for _, line := range lines {
+		var entry struct {
+			Code  string `json:"code"`
+			Count int64  `json:"count"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(line), &entry))
+		counts[entry.Code] = entry.Count
+	}
+	assert.Equal(t, map[string]int64{"object_storage_download": 1000, "pcm_forwarding": 1}, counts)
+
+	buf.Reset()
+	r.Flush(context.Background())
+	assert.Empty(t, buf.String())
+}
+
+// TestReporterDefaultWindow verifies that a [Reporter] created without a
+// window uses [DefaultReportWindow], so that it can be started.
+func TestReporterDefaultWindow(t *testing.T) {
+	r := NewReporter("test", zerolog.Nop(), nil, 0)
+	assert.Equal(t, DefaultReportWindow, r.window)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var wg sync.WaitGroup
+	r.Start(ctx, &wg)
+	cancel()
+	wg.Wait()
+}
@@ -15,3 +15,13 @@ var ErrObjectStorageUpload = fmt.Errorf("error uploading data to object storage"
 // disruptions, access permission problems, or other unforeseen factors that
 // interfere with the ability to download the desired content.
 var ErrObjectStorageDownload = fmt.Errorf("error downloading data from object storage")
+
+// ErrObjectStorageDelete represents an error encountered when attempting to
+// delete an object from an object storage service, because of network
+// disruptions, access permission problems or other conditions preventing the
+// removal.
+var ErrObjectStorageDelete = fmt.Errorf("error deleting data from object storage")
+
+// ErrObjectNotFound represents the error condition where an object requested
+// from an object storage service does not exist at the given bucket and path.
+var ErrObjectNotFound = fmt.Errorf("object not found in object storage")
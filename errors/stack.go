@@ -0,0 +1,209 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+)
+
+// StackMode controls how much of the call stack is recorded when an [error] is
+// created through [New], [Wrap] or [NewError].
+type StackMode string
+
+const (
+	// StackNone disables stack capture. This is the default, keeping error
+	// creation on hot paths as cheap as possible.
+	StackNone StackMode = "none"
+
+	// StackCaller records only the frame that created the error.
+	StackCaller StackMode = "caller"
+
+	// StackFull records the full call stack, up to [maxStackDepth] frames.
+	StackFull StackMode = "full"
+)
+
+// maxStackDepth bounds the number of frames recorded in [StackFull] mode.
+const maxStackDepth = 32
+
+// stackMode holds the active [StackMode] as an int32, so that it can be read
+// on every error creation without locking.
+var stackMode atomic.Int32
+
+// stackModes maps the values stored in stackMode to their [StackMode].
+var stackModes = []StackMode{StackNone, StackCaller, StackFull}
+
+// SetStackMode changes the [StackMode] used for errors created from now on.
+// Unknown modes disable stack capture.
+func SetStackMode(mode StackMode) {
+	for i, m := range stackModes {
+		if m == mode {
+			stackMode.Store(int32(i))
+			return
+		}
+	}
+
+	stackMode.Store(0)
+}
+
+// GetStackMode returns the active [StackMode].
+func GetStackMode() StackMode {
+	return stackModes[stackMode.Load()]
+}
+
+// Frame describes a single function call recorded in a [Stack].
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// String renders the frame as "function file:line".
+func (f Frame) String() string {
+	return f.Function + " " + f.File + ":" + strconv.Itoa(f.Line)
+}
+
+// Stack is a sequence of program counters recorded when an [error] was
+// created. Frames are resolved lazily, only when the stack is rendered.
+type Stack []uintptr
+
+// Frames resolves the program counters of the stack into [Frame]s, innermost
+// call first.
+func (s Stack) Frames() []Frame {
+	if len(s) == 0 {
+		return nil
+	}
+
+	frames := make([]Frame, 0, len(s))
+	it := runtime.CallersFrames(s)
+
+	for {
+		f, more := it.Next()
+		frames = append(frames, Frame{Function: f.Function, File: f.File, Line: f.Line})
+		if !more {
+			break
+		}
+	}
+
+	return frames
+}
+
+// Format writes one frame per line, each preceded by a newline and indented
+// with a tab, so that the stack can be appended to an error message.
+func (s Stack) Format(st fmt.State, verb rune) {
+	for _, f := range s.Frames() {
+		_, _ = io.WriteString(st, "\n\t"+f.String())
+	}
+}
+
+// callers records the call stack according to the active [StackMode],
+// skipping the given number of frames above its own caller. It returns nil
+// when stack capture is disabled.
+func callers(skip int) Stack {
+	var depth int
+
+	switch GetStackMode() {
+	case StackNone:
+		return nil
+	case StackCaller:
+		depth = 1
+	case StackFull:
+		depth = maxStackDepth
+	}
+
+	return captureStack(skip+1, depth)
+}
+
+// captureStack records up to depth frames of the call stack, skipping the
+// given number of frames above its own caller, regardless of the active
+// [StackMode].
+func captureStack(skip int, depth int) Stack {
+	pcs := make([]uintptr, depth)
+	n := runtime.Callers(skip+2, pcs)
+
+	return pcs[:n]
+}
+
+// withStack wraps an [error] created by [New] or [Wrap] with the call stack
+// recorded at its creation.
+type withStack struct {
+	err   error
+	stack Stack
+}
+
+// Error returns the message of the wrapped [error].
+func (w *withStack) Error() string {
+	return w.err.Error()
+}
+
+// Unwrap exposes the wrapped [error] to [Is] and [As].
+func (w *withStack) Unwrap() error {
+	return w.err
+}
+
+// Format implements [fmt.Formatter]. The %+v verb appends the recorded stack
+// to the message; every other verb renders the message only.
+func (w *withStack) Format(st fmt.State, verb rune) {
+	formatError(st, verb, w.err.Error(), w.stack)
+}
+
+// attachStack wraps err with the call stack of its creator, skipping the
+// given number of frames above the caller of attachStack. It returns err
+// unchanged when stack capture is disabled.
+func attachStack(err error, skip int) error {
+	stack := callers(skip + 1)
+	if stack == nil {
+		return err
+	}
+
+	return &withStack{err: err, stack: stack}
+}
+
+// StackOf returns the stack recorded closest to the origin of err, that is
+// the innermost one found in its chain. It returns nil if no stack was
+// recorded.
+func StackOf(err error) Stack {
+	var stack Stack
+
+	walk(err, func(err error) bool {
+		switch x := err.(type) {
+		case *withStack:
+			stack = x.stack
+		case *Error:
+			if x.stack != nil {
+				stack = x.stack
+			}
+		}
+		return false
+	})
+
+	return stack
+}
+
+// MarshalStack returns the [Frame]s of the stack recorded in err, or nil if
+// none was recorded. Its signature matches zerolog.ErrorStackMarshaler so that
+// stacks are written as a structured field of error log events.
+func MarshalStack(err error) interface{} {
+	stack := StackOf(err)
+	if stack == nil {
+		return nil
+	}
+
+	return stack.Frames()
+}
+
+// formatError writes msg to st and, for the %+v verb, the given stack.
+func formatError(st fmt.State, verb rune, msg string, stack Stack) {
+	switch verb {
+	case 'v':
+		_, _ = io.WriteString(st, msg)
+		if st.Flag('+') {
+			stack.Format(st, verb)
+		}
+	case 's':
+		_, _ = io.WriteString(st, msg)
+	case 'q':
+		_, _ = fmt.Fprintf(st, "%q", msg)
+	}
+}
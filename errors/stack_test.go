@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStackDisabled verifies that no stack is recorded by default and that
+// [New] and [Wrap] keep their original messages.
+func TestStackDisabled(t *testing.T) {
+	SetStackMode(StackNone)
+
+	err := Wrap(ErrInvalidEnv, New("env: %s", "SE_GA_LOG_FORMAT"))
+
+	assert.Nil(t, StackOf(err))
+	assert.Nil(t, MarshalStack(err))
+	assert.Equal(t, "invalid environment variable\nenv: SE_GA_LOG_FORMAT", fmt.Sprintf("%+v", err))
+}
+
+// TestStackCaller verifies that the caller frame of [New], [Wrap] and
+// [NewError] is recorded and rendered by the %+v verb only.
+func TestStackCaller(t *testing.T) {
+	SetStackMode(StackCaller)
+	defer SetStackMode(StackNone)
+
+	for _, err := range []error{
+		New("boom"),
+		Wrap(ErrObjectStorageUpload, fmt.Errorf("boom")),
+		NewError(ErrObjectStorageUpload),
+	} {
+		frames := StackOf(err).Frames()
+		assert.Len(t, frames, 1)
+		assert.True(t, strings.HasSuffix(frames[0].Function, "TestStackCaller"))
+		assert.True(t, strings.HasSuffix(frames[0].File, "stack_test.go"))
+
+		assert.NotContains(t, fmt.Sprintf("%v", err), "stack_test.go")
+		assert.Contains(t, fmt.Sprintf("%+v", err), "stack_test.go")
+	}
+}
+
+// TestStackFull verifies that the full stack is recorded and that the
+// innermost stack of a chain is reported.
+func TestStackFull(t *testing.T) {
+	SetStackMode(StackFull)
+	defer SetStackMode(StackNone)
+
+	inner := func() error { return New("inner") }()
+	err := Wrap(ErrPCMForwarding, inner)
+
+	frames := StackOf(err).Frames()
+	assert.Greater(t, len(frames), 1)
+	assert.Contains(t, frames[0].Function, "TestStackFull.func1")
+
+	SetStackMode("unknown")
+	assert.Equal(t, StackNone, GetStackMode())
+}
@@ -3,33 +3,39 @@ package gcp
 import (
 	"context"
 	"io"
+	"sync"
 
 	"github.com/stellarentropy/gravity-assist-common/metrics/datacounter"
 
 	"github.com/stellarentropy/gravity-assist-common/errors"
 
 	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
 )
 
 // storageClient manages interactions with Google Cloud Storage, facilitating
 // operations such as generating signed URLs, uploading and downloading data to
-// and from buckets, and establishing data transfer streams.
+// and from buckets, and establishing data transfer streams. It is created on
+// first use by [client], so that importing this package does not require
+// Google Cloud credentials.
 var storageClient *storage.Client
 
-// init establishes a connection to Google Cloud Storage and initializes the
-// global storageClient variable, panicking if the client creation fails.
-func init() {
-	// Declare an error variable
-	var err error
+// storageClientErr holds the error met while creating storageClient, if any.
+var storageClientErr error
 
-	// Create a new storage client and assign it to the global storageClient variable
-	// The context.Background() is used as the context for this operation
-	storageClient, err = storage.NewClient(context.Background())
+// storageClientOnce guards the creation of storageClient.
+var storageClientOnce sync.Once
 
-	// If an error occurred while creating the client, panic and stop the execution
-	if err != nil {
-		panic(err)
-	}
+// client returns the global storageClient, establishing the connection to
+// Google Cloud Storage on first use.
+func client() (*storage.Client, error) {
+	storageClientOnce.Do(func() {
+		// The context.Background() is used as the context for this operation,
+		// as the client outlives the request that first needs it
+		storageClient, storageClientErr = storage.NewClient(context.Background())
+	})
+
+	return storageClient, storageClientErr
 }
 
 // Upload transfers data from an [io.Reader] to a specified path within a Google
@@ -67,11 +73,17 @@ func Upload(ctx context.Context, component string, bucket string, path string, r
 // creation of the writer, it returns the writer along with any error that may
 // have occurred during setup.
 func GetUploadWriter(ctx context.Context, component string, bucket string, path string) (io.WriteCloser, error) {
+	// Get the client, connecting to Google Cloud Storage on first use
+	sc, err := client()
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrObjectStorageUpload, err)
+	}
+
 	// Create a new writer for the specified bucket and object path
-	wc := storageClient.Bucket(bucket).Object(path).NewWriter(ctx)
+	wc := sc.Bucket(bucket).Object(path).NewWriter(ctx)
 
 	// Create a new counter for the writer to track the amount of data written
-	counter := datacounter.NewObjectStorageWriterCounter(ctx, component, wc, storageClient)
+	counter := datacounter.NewObjectStorageWriterCounter(ctx, component, wc, sc)
 
 	// Return the counter (which also acts as a writer) and nil for the error
 	return counter, nil
@@ -112,19 +124,98 @@ func Download(ctx context.Context, component string, bucket string, path string,
 // operations are supported. In the event of an error during reader creation,
 // the error is returned along with a nil reader.
 func GetDownloadReader(ctx context.Context, component string, bucket string, path string, seeker bool) (io.ReadCloser, error) {
+	// Get the client, connecting to Google Cloud Storage on first use
+	sc, err := client()
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrObjectStorageDownload, err)
+	}
+
 	// Get a handle to the specified object in the bucket
-	handle := storageClient.Bucket(bucket).Object(path)
+	handle := sc.Bucket(bucket).Object(path)
 
 	// Create a new reader for the object
 	rc, err := handle.NewReader(ctx)
 	if err != nil {
 		// If there's an error during the reader creation, wrap it with a custom error and return
-		return nil, errors.Wrap(errors.ErrObjectStorageDownload, err)
+		return nil, wrapNotFound(errors.ErrObjectStorageDownload, err)
 	}
 
 	// Create a new counter for the reader to track the amount of data read
-	counter := datacounter.NewObjectStorageReaderCounter(ctx, component, rc, storageClient, handle, seeker)
+	counter := datacounter.NewObjectStorageReaderCounter(ctx, component, rc, sc, handle, seeker)
 
 	// Return the counter
 	return counter, nil
 }
+
+// Stat retrieves the attributes of the object at the specified path within a
+// Google Cloud Storage bucket, such as its size and last update time. If the
+// object does not exist, the returned error wraps [errors.ErrObjectNotFound].
+func Stat(ctx context.Context, bucket string, path string) (*storage.ObjectAttrs, error) {
+	// Get the client, connecting to Google Cloud Storage on first use
+	sc, err := client()
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrObjectStorageDownload, err)
+	}
+
+	attrs, err := sc.Bucket(bucket).Object(path).Attrs(ctx)
+	if err != nil {
+		return nil, wrapNotFound(errors.ErrObjectStorageDownload, err)
+	}
+
+	return attrs, nil
+}
+
+// Delete removes the object at the specified path within a Google Cloud
+// Storage bucket. If the object does not exist, the returned error wraps
+// [errors.ErrObjectNotFound].
+func Delete(ctx context.Context, bucket string, path string) error {
+	// Get the client, connecting to Google Cloud Storage on first use
+	sc, err := client()
+	if err != nil {
+		return errors.Wrap(errors.ErrObjectStorageDelete, err)
+	}
+
+	if err := sc.Bucket(bucket).Object(path).Delete(ctx); err != nil {
+		return wrapNotFound(errors.ErrObjectStorageDelete, err)
+	}
+
+	return nil
+}
+
+// List retrieves the attributes of every object of a Google Cloud Storage
+// bucket whose path starts with the given prefix, in lexicographic order of
+// their paths.
+func List(ctx context.Context, bucket string, prefix string) ([]*storage.ObjectAttrs, error) {
+	// Get the client, connecting to Google Cloud Storage on first use
+	sc, err := client()
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrObjectStorageDownload, err)
+	}
+
+	var objects []*storage.ObjectAttrs
+
+	it := sc.Bucket(bucket).Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrObjectStorageDownload, err)
+		}
+
+		objects = append(objects, attrs)
+	}
+
+	return objects, nil
+}
+
+// wrapNotFound wraps err with the given sentinel, preceded by
+// [errors.ErrObjectNotFound] if it reports a missing object.
+func wrapNotFound(sentinel error, err error) error {
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return errors.Wrap(errors.ErrObjectNotFound, sentinel, err)
+	}
+
+	return errors.Wrap(sentinel, err)
+}
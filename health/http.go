@@ -1,3 +1,5 @@
+//go:generate go run ../cmd/componentgen -name health
+
 package health
 
 import (
@@ -40,15 +42,25 @@ func getRouter() *chi.Mux {
 	return router
 }
 
-func Listen(ctx context.Context) error {
-	listener, err := net.Listen("tcp",
-		net.JoinHostPort(
-			common_config.Common.HealthListenAddress,
-			fmt.Sprintf("%d", common_config.Common.HealthListenPort)))
+func Listen(ctx context.Context, c *common_config.CommonConfig) error {
+	listener, err := listen(c)
 	if err != nil {
 		return err
 	}
 
+	return serve(ctx, listener, c)
+}
+
+// listen binds the listener of the health server.
+func listen(c *common_config.CommonConfig) (net.Listener, error) {
+	return net.Listen("tcp",
+		net.JoinHostPort(
+			c.HealthListenAddress,
+			fmt.Sprintf("%d", c.HealthListenPort)))
+}
+
+// serve runs the health server on listener until ctx is done.
+func serve(ctx context.Context, listener net.Listener, c *common_config.CommonConfig) error {
 	// Log the starting of the health server
 	logger.Info().
 		Str("address", listener.Addr().(*net.TCPAddr).IP.String()).
@@ -63,8 +75,8 @@ func Listen(ctx context.Context) error {
 	server := &http.Server{
 		Addr:         addr,
 		Handler:      h2c.NewHandler(router, &http2.Server{}),
-		ReadTimeout:  common_config.Common.HealthReadTimeout,
-		WriteTimeout: common_config.Common.HealthWriteTimeout,
+		ReadTimeout:  c.HealthReadTimeout,
+		WriteTimeout: c.HealthWriteTimeout,
 	}
 
 	// Start a new goroutine that listens for the context cancellation signal
@@ -74,7 +86,7 @@ func Listen(ctx context.Context) error {
 		// Log the shutting down of the health server
 		logger.Info().Msg("shutting down health server")
 
-		tctx, cancel := context.WithTimeout(context.Background(), common_config.Common.GracefulShutdownTimeout)
+		tctx, cancel := context.WithTimeout(context.Background(), c.GracefulShutdownTimeout)
 		defer cancel()
 
 		// Attempt to gracefully shut down the server and log any errors
@@ -87,15 +99,35 @@ func Listen(ctx context.Context) error {
 	return server.Serve(listener)
 }
 
-func Start(ctx context.Context, wg *sync.WaitGroup) {
+// start starts the health server on behalf of [Component], whose Start
+// cannot return the error, panicking if it cannot.
+func start(ctx context.Context, wg *sync.WaitGroup, c *common_config.CommonConfig) {
+	if err := Start(ctx, wg, c); err != nil {
+		panic(err)
+	}
+}
+
+// Start binds the listener of the health server, returning the error if it
+// cannot, and serves it in the background. A failure of the server once
+// started is logged as a structured error and still takes down the process,
+// as liveness probes cannot reach it anymore.
+func Start(ctx context.Context, wg *sync.WaitGroup, c *common_config.CommonConfig) error {
+	listener, err := listen(c)
+	if err != nil {
+		logger.Error().Stack().Err(err).Msg("error starting health server")
+		return err
+	}
+
 	wg.Add(1)
 
 	go func() {
 		defer wg.Done()
+		defer errors.Repanic(componentName, logger.Logger)
 
-		if err := Listen(ctx); err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, http.ErrServerClosed) {
-			logger.Error().Err(err).Msg("error in health server")
+		if err := serve(ctx, listener, c); err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, http.ErrServerClosed) {
 			panic(err)
 		}
 	}()
+
+	return nil
 }
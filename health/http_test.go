@@ -0,0 +1,32 @@
+package health
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stellarentropy/gravity-assist-common/config/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStartPortInUse verifies that [Start] fails when its port is already
+// bound, instead of leaving the service running without a health server.
+func TestStartPortInUse(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	c, err := common_config.FromMap(map[string]string{
+		"SE_GA_HEALTH_LISTEN_ADDRESS": "127.0.0.1",
+		"SE_GA_HEALTH_LISTEN_PORT":    strconv.Itoa(listener.Addr().(*net.TCPAddr).Port),
+	})
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	assert.Error(t, Start(context.Background(), &wg, c))
+	assert.Panics(t, func() { NewComponent(c).Start(context.Background(), &wg) })
+
+	wg.Wait()
+}
@@ -3,38 +3,68 @@ package logging
 import (
 	"fmt"
 	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/diode"
+	"github.com/stellarentropy/gravity-assist-common/config"
 	"github.com/stellarentropy/gravity-assist-common/config/common"
+	"github.com/stellarentropy/gravity-assist-common/errors"
 )
 
-// GetLogger returns a preconfigured [zerolog.Logger] for application-wide
-// logging, ensuring timestamped and formatted output with efficient message
-// processing, including the ability to drop messages under high load to
-// maintain system performance.
-func GetLogger() Logger {
+// output is the writer shared by every logger returned by [GetLogger]. It
+// writes JSON to the standard output until [Configure] selects the writer
+// matching the configured log format, so that loggers can be created at
+// package init, before the configuration is loaded.
+var output = &switchWriter{}
+
+// init applies the global zerolog settings, which do not depend on the
+// configuration.
+func init() {
 	// Set the global duration field unit to milliseconds
 	zerolog.DurationFieldUnit = time.Millisecond
 	// Set the global time field format to RFC3339
 	zerolog.TimeFieldFormat = time.RFC3339
+	// Write the stack recorded by the errors package, if any, alongside logged errors
+	zerolog.ErrorStackMarshaler = errors.MarshalStack
+	// Set the global log level to Info until the configuration is applied
+	zerolog.SetGlobalLevel(zerolog.InfoLevel)
 
-	// Declare a variable to hold the multi-level writer
-	var multi zerolog.LevelWriter
+	output.set(zerolog.MultiLevelWriter(os.Stdout))
+}
 
-	var wr diode.Writer
+// GetLogger returns a preconfigured [zerolog.Logger] for application-wide
+// logging, ensuring timestamped and formatted output with efficient message
+// processing, including the ability to drop messages under high load to
+// maintain system performance. The format and level of the output follow the
+// configuration applied with [Configure], even for loggers created earlier.
+func GetLogger() Logger {
+	// Create a new logger with the shared writer, add a timestamp to each log message
+	// and the recorded stack to each logged error
+	logger := zerolog.New(output).With().Timestamp().Stack().Logger()
+
+	// Return the logger
+	return Logger{logger}
+}
 
-	logFormat := common_config.Common.LogFormat
+// Configure applies the logging settings of the given configuration to every
+// logger returned by [GetLogger]: the output format, the global log level and
+// the stack capture mode of the errors package. It then logs the effective
+// configuration, with its secrets masked. It is meant to be called once, right
+// after the configuration is loaded.
+func Configure(c *common_config.CommonConfig) {
+	// Declare a variable to hold the diode writer
+	var wr diode.Writer
 
-	switch logFormat {
+	switch c.LogFormat {
 	case "color", "text":
 		// Create a new diode writer with a buffer size of 1000 and a flush interval of 10 milliseconds
-		wr = diode.NewWriter(consoleWriter(), 1000, 10*time.Millisecond, func(missed int) {
+		wr = diode.NewWriter(consoleWriter(c.LogFormat), 1000, 10*time.Millisecond, func(missed int) {
 			// If any messages are dropped, print the number of dropped messages
 			fmt.Printf("Dropped %d messages", missed)
 		})
-	case "json":
+	default:
 		// Create a new diode writer with a buffer size of 1000 and a flush interval of 10 milliseconds
 		wr = diode.NewWriter(os.Stdout, 1000, 10*time.Millisecond, func(missed int) {
 			// If any messages are dropped, print the number of dropped messages
@@ -42,26 +72,94 @@ func GetLogger() Logger {
 		})
 	}
 
-	// Assign the diode writer to the multi-level writer
-	multi = zerolog.MultiLevelWriter(wr)
+	// Assign the diode writer to the shared writer
+	output.set(zerolog.MultiLevelWriter(wr))
 
-	// Create a new logger with the multi-level writer, add a timestamp to each log message
-	logger := zerolog.New(multi).With().Timestamp().Logger()
+	apply(c)
 
-	// Set the global log level to Info
-	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	// Log the profile and the configuration the service starts with, with its
+	// secrets masked
+	logger := GetLogger()
+	logger.Info().Str("profile", c.Profile).Msgf("starting with the %s profile", c.Profile)
 
-	// Return the logger
-	return Logger{logger}
+	if dump, err := c.Dump(); err != nil {
+		logger.Error().Err(err).Msg("error rendering configuration")
+	} else {
+		logger.Info().RawJSON("config", dump).Msg("effective configuration")
+	}
+
+	// Warn about deprecated keys, so that they are renamed before they are
+	// dropped
+	for _, d := range c.Deprecations() {
+		logger.Warn().
+			Str("env", d.Key).
+			Str("replacement", d.Replacement).
+			Str("since", d.Since).
+			Msg(d.String())
+	}
+}
+
+// Follow keeps the global log level and the stack capture mode in line with
+// the configuration held by r as it is reloaded, and logs failed reloads. The
+// output format is fixed by [Configure].
+func Follow(r *config.Reloadable[common_config.CommonConfig]) {
+	logger := GetLogger()
+
+	r.Subscribe(func(_, c *common_config.CommonConfig) {
+		apply(c)
+	})
+
+	r.OnError(func(err error) {
+		logger.Error().Err(err).Msg("error reloading configuration")
+	})
+}
+
+// apply sets the settings of the given configuration that can change at
+// runtime.
+func apply(c *common_config.CommonConfig) {
+	// Set the global log level to the configured one
+	zerolog.SetGlobalLevel(level(c.LogLevel))
+	// Record the stack of created errors as configured
+	errors.SetStackMode(errors.StackMode(c.ErrorStackCapture))
+}
+
+// level parses a log level accepted by the configuration, defaulting to
+// [zerolog.InfoLevel].
+func level(s string) zerolog.Level {
+	l, err := zerolog.ParseLevel(s)
+	if err != nil || l == zerolog.NoLevel {
+		return zerolog.InfoLevel
+	}
+
+	return l
+}
+
+// switchWriter is a [zerolog.LevelWriter] forwarding to a writer that can be
+// replaced at any time, without synchronizing with the loggers using it.
+type switchWriter struct {
+	w atomic.Pointer[zerolog.LevelWriter]
+}
+
+// set replaces the writer the switchWriter forwards to.
+func (s *switchWriter) set(w zerolog.LevelWriter) {
+	s.w.Store(&w)
+}
+
+// Write forwards p to the current writer.
+func (s *switchWriter) Write(p []byte) (int, error) {
+	return (*s.w.Load()).Write(p)
+}
+
+// WriteLevel forwards p, logged at level l, to the current writer.
+func (s *switchWriter) WriteLevel(l zerolog.Level, p []byte) (int, error) {
+	return (*s.w.Load()).WriteLevel(l, p)
 }
 
 // consoleWriter creates and returns a [zerolog.ConsoleWriter] that formats log
 // messages for display in the console, incorporating features like color
 // coding, time stamps, caller information, log levels, and message content
 // arranged in a readable tabular format with fixed message width.
-func consoleWriter() zerolog.ConsoleWriter {
-	logFormat := common_config.Common.LogFormat
-
+func consoleWriter(logFormat string) zerolog.ConsoleWriter {
 	var color bool
 
 	if logFormat == "color" {
@@ -0,0 +1,95 @@
+package datacounter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/stellarentropy/gravity-assist-common/metrics/tracer"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ObjectReaderCounter tracks the amount of data read from an object of any
+// object storage backend, recording the same object_storage.bytes.read metric
+// as [ObjectStorageReaderCounter] does for Google Cloud Storage. It wraps the
+// [io.ReadCloser] returned by the backend and supports random-access reads
+// when the wrapped reader implements [io.ReaderAt].
+type ObjectReaderCounter struct {
+	ctx       context.Context
+	count     uint64
+	component string
+	bucket    string
+	Reader    io.ReadCloser
+}
+
+// NewObjectReaderCounter creates a new instance of [ObjectReaderCounter]
+// wrapping r, which reads an object of the given bucket, and attributing the
+// bytes read to the given component.
+func NewObjectReaderCounter(ctx context.Context, component string, bucket string, r io.ReadCloser) *ObjectReaderCounter {
+	return &ObjectReaderCounter{
+		Reader:    r,
+		ctx:       ctx,
+		component: component,
+		bucket:    bucket,
+	}
+}
+
+// Read retrieves data from the underlying [io.Reader] into the provided buffer,
+// updating the read byte count and the metrics of the object storage reads.
+func (counter *ObjectReaderCounter) Read(buf []byte) (int, error) {
+	n, err := counter.Reader.Read(buf)
+
+	counter.add(n)
+
+	return n, err
+}
+
+// ReadAt reads data from the object starting at the given offset into the
+// provided buffer, updating the read byte count and the metrics as
+// [ObjectReaderCounter.Read] does. It fails if the underlying reader does not
+// implement [io.ReaderAt].
+func (counter *ObjectReaderCounter) ReadAt(buf []byte, off int64) (int, error) {
+	ra, ok := counter.Reader.(io.ReaderAt)
+	if !ok {
+		return 0, fmt.Errorf("reader of type %T does not support ReadAt", counter.Reader)
+	}
+
+	n, err := ra.ReadAt(buf, off)
+
+	counter.add(n)
+
+	return n, err
+}
+
+// add records n bytes read. Negative counts, which a faulty [io.Reader] may
+// return, are ignored.
+func (counter *ObjectReaderCounter) add(n int) {
+	if n < 0 {
+		return
+	}
+
+	atomic.AddUint64(&counter.count, uint64(n))
+
+	tracer.MustAddInt64(counter.ctx, counter.component, "object_storage.bytes.read", int64(n),
+		metric.AddOption(metric.WithAttributes(
+			attribute.KeyValue{
+				Key:   "bucket",
+				Value: attribute.StringValue(counter.bucket),
+			},
+		)),
+	)
+}
+
+// Count returns the cumulative number of bytes read from the object by this
+// instance.
+func (counter *ObjectReaderCounter) Count() uint64 {
+	return atomic.LoadUint64(&counter.count)
+}
+
+// Close closes the underlying [io.ReadCloser].
+func (counter *ObjectReaderCounter) Close() error {
+	return counter.Reader.Close()
+}
@@ -0,0 +1,86 @@
+package datacounter
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+
+	"github.com/stellarentropy/gravity-assist-common/metrics/tracer"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ObjectWriterCounter tracks the amount of data written to an object of any
+// object storage backend, recording the same object_storage.bytes.written
+// metric as [ObjectStorageWriterCounter] does for Google Cloud Storage. It
+// wraps the [io.WriteCloser] returned by the backend, whose Close commits the
+// object.
+type ObjectWriterCounter struct {
+	ctx       context.Context
+	count     uint64
+	component string
+	bucket    string
+	Writer    io.WriteCloser
+}
+
+// NewObjectWriterCounter creates a new instance of [ObjectWriterCounter]
+// wrapping w, which writes an object of the given bucket, and attributing the
+// bytes written to the given component.
+func NewObjectWriterCounter(ctx context.Context, component string, bucket string, w io.WriteCloser) *ObjectWriterCounter {
+	return &ObjectWriterCounter{
+		Writer:    w,
+		ctx:       ctx,
+		component: component,
+		bucket:    bucket,
+	}
+}
+
+// Write writes a slice of bytes to the underlying [io.Writer], updating the
+// written byte count and the metrics of the object storage writes.
+func (counter *ObjectWriterCounter) Write(buf []byte) (int, error) {
+	n, err := counter.Writer.Write(buf)
+
+	// Write() should always return a non-negative `n`.
+	// But since `n` is a signed integer, some custom
+	// implementation of an io.Writer may return negative
+	// values.
+	//
+	// Excluding such invalid values from counting,
+	// thus `if n >= 0`:
+	if n >= 0 {
+		atomic.AddUint64(&counter.count, uint64(n))
+
+		tracer.MustAddInt64(counter.ctx, counter.component, "object_storage.bytes.written", int64(n),
+			metric.AddOption(metric.WithAttributes(
+				attribute.KeyValue{
+					Key:   "bucket",
+					Value: attribute.StringValue(counter.bucket),
+				},
+			)),
+		)
+	}
+
+	return n, err
+}
+
+// Count returns the cumulative number of bytes written to the object by this
+// instance.
+func (counter *ObjectWriterCounter) Count() uint64 {
+	return atomic.LoadUint64(&counter.count)
+}
+
+// Close closes the underlying [io.WriteCloser], committing the object.
+func (counter *ObjectWriterCounter) Close() error {
+	return counter.Writer.Close()
+}
+
+// Abort discards the object if the underlying [io.WriteCloser] has an Abort
+// method, and closes it otherwise.
+func (counter *ObjectWriterCounter) Abort() error {
+	if a, ok := counter.Writer.(interface{ Abort() error }); ok {
+		return a.Abort()
+	}
+
+	return counter.Writer.Close()
+}
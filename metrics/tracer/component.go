@@ -5,18 +5,22 @@ package tracer
 import (
 	"context"
 	"sync"
+
+	"github.com/stellarentropy/gravity-assist-common/config/common"
 )
 
 var componentName = "gravity-assist.tracer"
 
-type Component struct{}
+type Component struct {
+	config *common_config.CommonConfig
+}
 
-func NewComponent() *Component {
-	return &Component{}
+func NewComponent(c *common_config.CommonConfig) *Component {
+	return &Component{config: c}
 }
 
 func (d *Component) Start(ctx context.Context, wg *sync.WaitGroup) {
-	Start(ctx, wg)
+	start(ctx, wg, d.config)
 }
 
 func (d *Component) Name() string {
@@ -0,0 +1,14 @@
+package tracer
+
+import (
+	"time"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+)
+
+// NewErrorReporter creates an [*errors.Reporter] for component that
+// summarizes errors over the given window through the tracer logger and
+// increments the errors.reported counter with [AddInt64].
+func NewErrorReporter(component string, window time.Duration) *errors.Reporter {
+	return errors.NewReporter(component, logger.Logger, AddInt64, window)
+}
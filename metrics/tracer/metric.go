@@ -3,8 +3,8 @@ package tracer
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 
-	config "github.com/stellarentropy/gravity-assist-common/config/common"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/metric"
 )
@@ -14,6 +14,11 @@ var metrics = make(map[string]metric.Meter)
 var counters = make(map[string]metric.Int64Counter)
 
 var metricsLock = sync.Mutex{}
+
+// metricCollection reports whether metrics are recorded, as enabled by the
+// configuration passed to [StartTracer]. Metrics are not recorded until the
+// tracer is started.
+var metricCollection atomic.Bool
 var countersLock = sync.Mutex{}
 
 func NewMetric(ctx context.Context, component string, opts ...metric.MeterOption) metric.Meter {
@@ -32,7 +37,7 @@ func NewMetric(ctx context.Context, component string, opts ...metric.MeterOption
 }
 
 func AddInt64(ctx context.Context, component string, name string, value int64, opts ...metric.AddOption) error {
-	if !config.Common.EnableMetricCollection {
+	if !metricCollection.Load() {
 		return nil
 	}
 
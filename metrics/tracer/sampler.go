@@ -0,0 +1,57 @@
+package tracer
+
+import (
+	"sync/atomic"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	configpkg "github.com/stellarentropy/gravity-assist-common/config"
+	config "github.com/stellarentropy/gravity-assist-common/config/common"
+)
+
+// reloadableSampler is an [sdktrace.Sampler] delegating to the sampler selected
+// by the current configuration, so that the trace sampler and its ratio can be
+// changed by a configuration reload without restarting the tracer provider.
+type reloadableSampler struct {
+	current atomic.Pointer[sdktrace.Sampler]
+}
+
+// sampler is the [reloadableSampler] of the tracer provider created by
+// [StartTracer], following the configuration reloads once [Follow] is called.
+var sampler = &reloadableSampler{}
+
+// Follow keeps the trace sampler and its ratio in line with the configuration
+// held by r as it is reloaded, without restarting the tracer provider.
+func Follow(r *configpkg.Reloadable[config.CommonConfig]) {
+	r.Subscribe(func(_, c *config.CommonConfig) {
+		sampler.set(c)
+	})
+}
+
+// set replaces the sampler with the one selected by the given configuration.
+func (s *reloadableSampler) set(c *config.CommonConfig) {
+	var next sdktrace.Sampler
+
+	switch c.TraceSampler {
+	case "always":
+		next = sdktrace.AlwaysSample()
+	case "never":
+		next = sdktrace.NeverSample()
+	case "traceIdRatio":
+		next = sdktrace.TraceIDRatioBased(c.TraceIdRatio)
+	default:
+		next = sdktrace.AlwaysSample()
+	}
+
+	s.current.Store(&next)
+}
+
+// ShouldSample delegates the sampling decision to the current sampler.
+func (s *reloadableSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return (*s.current.Load()).ShouldSample(p)
+}
+
+// Description returns the description of the current sampler.
+func (s *reloadableSampler) Description() string {
+	return (*s.current.Load()).Description()
+}
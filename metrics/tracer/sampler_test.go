@@ -0,0 +1,41 @@
+package tracer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	configpkg "github.com/stellarentropy/gravity-assist-common/config"
+	config "github.com/stellarentropy/gravity-assist-common/config/common"
+)
+
+// TestSamplerFollowsReloads verifies that the trace sampler switches to the
+// one selected by a reloaded configuration.
+func TestSamplerFollowsReloads(t *testing.T) {
+	values := map[string]string{"SE_GA_TRACE_SAMPLER": "never"}
+	load := func() (*config.CommonConfig, error) { return config.FromMap(values) }
+
+	c, err := load()
+	assert.NoError(t, err)
+
+	sampler.set(c)
+
+	r := configpkg.NewReloadable(c, load, time.Second)
+	Follow(r)
+
+	params := sdktrace.SamplingParameters{TraceID: trace.TraceID{1}}
+	assert.Equal(t, sdktrace.Drop, sampler.ShouldSample(params).Decision)
+
+	values["SE_GA_TRACE_SAMPLER"] = "always"
+	assert.NoError(t, r.Reload())
+	assert.Equal(t, sdktrace.RecordAndSample, sampler.ShouldSample(params).Decision)
+
+	values["SE_GA_TRACE_SAMPLER"] = "traceIdRatio"
+	values["SE_GA_TRACE_ID_RATIO"] = "0"
+	assert.NoError(t, r.Reload())
+	assert.Contains(t, sampler.Description(), "TraceIDRatioBased")
+	assert.Equal(t, sdktrace.Drop, sampler.ShouldSample(params).Decision)
+}
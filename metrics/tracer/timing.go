@@ -2,16 +2,21 @@ package tracer
 
 import (
 	"context"
+	"sync/atomic"
 
-	config "github.com/stellarentropy/gravity-assist-common/config/common"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
+// traceCollection reports whether spans are recorded, as enabled by the
+// configuration passed to [StartTracer]. Spans are not recorded until the
+// tracer is started.
+var traceCollection atomic.Bool
+
 func NewSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
-	if !config.Common.EnableTraceCollection {
+	if !traceCollection.Load() {
 		return ctx, &MockTracer{}
 	}
 
@@ -23,7 +28,7 @@ func NewSpan(ctx context.Context, name string, attributes ...attribute.KeyValue)
 }
 
 func RecordError(span trace.Span, description string, err error) {
-	if config.Common.EnableTraceCollection {
+	if traceCollection.Load() {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, description)
 	}
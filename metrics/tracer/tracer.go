@@ -1,3 +1,5 @@
+//go:generate go run ../../cmd/componentgen -name tracer
+
 package tracer
 
 import (
@@ -5,11 +7,14 @@ import (
 	"sync"
 
 	config "github.com/stellarentropy/gravity-assist-common/config/common"
+	"github.com/stellarentropy/gravity-assist-common/errors"
 
 	mexporter "github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/metric"
 	texporter "github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/trace"
 	"go.opentelemetry.io/contrib/detectors/gcp"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
@@ -17,13 +22,13 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
 )
 
-func StartTracer(ctx context.Context) (*sdktrace.TracerProvider, error) {
-	gcpTraceExporter, err := texporter.New(texporter.WithProjectID(config.Common.GoogleProjectId))
+func StartTracer(ctx context.Context, c *config.CommonConfig) (*sdktrace.TracerProvider, error) {
+	gcpTraceExporter, err := texporter.New(texporter.WithProjectID(c.GoogleProjectId))
 	if err != nil {
 		return nil, err
 	}
 
-	gcpMetricExporter, err := mexporter.New(mexporter.WithProjectID(config.Common.GoogleProjectId))
+	gcpMetricExporter, err := mexporter.New(mexporter.WithProjectID(c.GoogleProjectId))
 	if err != nil {
 		return nil, err
 	}
@@ -32,32 +37,23 @@ func StartTracer(ctx context.Context) (*sdktrace.TracerProvider, error) {
 		resource.WithDetectors(gcp.NewDetector()),
 		resource.WithTelemetrySDK(),
 		resource.WithAttributes(
-			semconv.ServiceNameKey.String(config.Common.ServiceName),
+			semconv.ServiceNameKey.String(c.ServiceName),
 		),
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	var traceSampler sdktrace.Sampler
-
-	switch config.Common.TraceSampler {
-	case "always":
-		traceSampler = sdktrace.AlwaysSample()
-	case "never":
-		traceSampler = sdktrace.NeverSample()
-	case "traceIdRatio":
-		traceSampler = sdktrace.TraceIDRatioBased(config.Common.TraceIdRatio)
-	}
+	sampler.set(c)
 
 	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithSampler(traceSampler),
+		sdktrace.WithSampler(sampler),
 		sdktrace.WithBatcher(gcpTraceExporter),
 		sdktrace.WithResource(res),
 	)
 
 	metricReader := sdkmetric.NewPeriodicReader(gcpMetricExporter,
-		sdkmetric.WithInterval(config.Common.MetricExportInterval))
+		sdkmetric.WithInterval(c.MetricExportInterval))
 
 	mp := sdkmetric.NewMeterProvider(
 		sdkmetric.WithReader(metricReader),
@@ -68,20 +64,42 @@ func StartTracer(ctx context.Context) (*sdktrace.TracerProvider, error) {
 	otel.SetMeterProvider(mp)
 	otel.SetTextMapPropagator(propagation.TraceContext{})
 
+	traceCollection.Store(c.EnableTraceCollection)
+	metricCollection.Store(c.EnableMetricCollection)
+
+	// Count the deprecated keys the configuration was read from, now that
+	// metrics can be recorded
+	for _, d := range c.Deprecations() {
+		MustAddInt64(ctx, componentName, "config.deprecated_keys", 1,
+			metric.AddOption(metric.WithAttributes(
+				attribute.String("env", d.Key),
+				attribute.String("replacement", d.Replacement),
+			)),
+		)
+	}
+
 	return tp, nil
 }
 
-func Start(ctx context.Context, wg *sync.WaitGroup) {
+// start starts the tracer on behalf of [Component].
+func start(ctx context.Context, wg *sync.WaitGroup, c *config.CommonConfig) {
+	Start(ctx, wg, c)
+}
+
+func Start(ctx context.Context, wg *sync.WaitGroup, c *config.CommonConfig) {
 	defer wg.Done()
+	// Report a failure to start or stop the tracer as a structured error,
+	// still taking down the process as it cannot run without metrics
+	defer errors.Repanic(componentName, logger.Logger)
 
-	tp, err := StartTracer(ctx)
+	tp, err := StartTracer(ctx, c)
 	if err != nil {
 		logger.Error().Err(err).Msg("error starting tracer")
 		panic(err)
 	}
 
 	defer func() {
-		tctx, cancel := context.WithTimeout(context.Background(), config.Common.GracefulShutdownTimeout)
+		tctx, cancel := context.WithTimeout(context.Background(), c.GracefulShutdownTimeout)
 		defer cancel()
 
 		if err := tp.Shutdown(tctx); err != nil {
@@ -0,0 +1,67 @@
+package objectstorage
+
+import (
+	"net/url"
+
+	"github.com/stellarentropy/gravity-assist-common/config"
+)
+
+// BackendKey is the environment variable selecting the [ObjectStorage]
+// backend created by [New].
+const BackendKey = "SE_GA_OBJECT_STORAGE_BACKEND"
+
+// Backends accepted by [BackendKey].
+const (
+	BackendGCS        = "gcs"
+	BackendFilesystem = "filesystem"
+	BackendMemory     = "memory"
+	BackendS3         = "s3"
+)
+
+// Config selects and configures the [ObjectStorage] backend created by [New].
+// Each field is bound to its environment variable through struct tags, see
+// [config.Loader.Bind]. The test profile defaults to the in-memory backend,
+// so that tests never reach Google Cloud Storage or S3.
+type Config struct {
+	Backend   string `env:"SE_GA_OBJECT_STORAGE_BACKEND" default:"gcs" default.test:"memory" options:"gcs,filesystem,memory,s3" required:"true" description:"Backend storing the objects."`
+	Directory string `env:"SE_GA_OBJECT_STORAGE_DIRECTORY" kind:"directory-or-create" requiredif:"SE_GA_OBJECT_STORAGE_BACKEND=filesystem" description:"Root directory of the filesystem backend, holding one directory per bucket."`
+
+	S3Endpoint        *url.URL        `env:"SE_GA_OBJECT_STORAGE_S3_ENDPOINT" description:"Endpoint of the S3-compatible service, such as http://localhost:9000 for MinIO. Defaults to the AWS endpoint of the region."`
+	S3Region          string          `env:"SE_GA_OBJECT_STORAGE_S3_REGION" default:"us-east-1" description:"Region of the S3 buckets, used to sign the requests."`
+	S3AccessKeyID     string          `env:"SE_GA_OBJECT_STORAGE_S3_ACCESS_KEY_ID" requiredif:"SE_GA_OBJECT_STORAGE_BACKEND=s3" description:"Access key ID signing the requests of the S3 backend."`
+	S3SecretAccessKey string          `env:"SE_GA_OBJECT_STORAGE_S3_SECRET_ACCESS_KEY" secret:"true" requiredif:"SE_GA_OBJECT_STORAGE_BACKEND=s3" description:"Secret access key signing the requests of the S3 backend."`
+	S3SessionToken    string          `env:"SE_GA_OBJECT_STORAGE_S3_SESSION_TOKEN" secret:"true" description:"Session token of temporary credentials of the S3 backend."`
+	S3PathStyle       bool            `env:"SE_GA_OBJECT_STORAGE_S3_PATH_STYLE" default:"false" description:"Whether the bucket is addressed in the path of the URL rather than in the host name, as MinIO usually requires."`
+	S3PartSize        config.ByteSize `env:"SE_GA_OBJECT_STORAGE_S3_PART_SIZE" default:"16MiB" description:"Size of the parts of the multipart uploads of the S3 backend, used for larger objects. At least 5MiB."`
+}
+
+// init registers the variables of [Config], so that they are documented in
+// the configuration reference generated by cmd/configdoc.
+func init() {
+	config.Register("object-storage", &Config{})
+}
+
+// LoadConfig reads the [Config] from the environment variables of the process
+// and the configuration file selected by [config.ConfigFileKey]. It returns a
+// [*config.LoadError] listing every problem at once if the configuration is
+// invalid.
+func LoadConfig() (*Config, error) {
+	l := config.NewLoader()
+	c := NewConfig(l)
+
+	if err := l.Err(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// NewConfig binds the [Config] to the environment through the given
+// [config.Loader], which collects every validation failure instead of
+// panicking on the first one.
+func NewConfig(l *config.Loader) *Config {
+	c := &Config{}
+	l.Bind(c)
+
+	return c
+}
@@ -0,0 +1,262 @@
+package objectstorage
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/stellarentropy/gravity-assist-common/metrics/datacounter"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+)
+
+// tempPrefix starts the names of the files being uploaded to a [Filesystem],
+// which are not listed.
+const tempPrefix = ".upload-"
+
+// Filesystem is the [ObjectStorage] backend storing objects as files of a
+// local directory, with one subdirectory per bucket, so that services can run
+// on a laptop without Google Cloud Storage.
+type Filesystem struct {
+	root string
+}
+
+// NewFilesystem creates the filesystem backend storing objects under the
+// given root directory, which is created if needed.
+func NewFilesystem(root string) (*Filesystem, error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, errors.Wrap(errors.ErrInvalidPath, err)
+	}
+
+	return &Filesystem{root: root}, nil
+}
+
+// Upload copies r to the file of the object.
+func (s *Filesystem) Upload(ctx context.Context, component string, bucket string, path string, r io.Reader) error {
+	wc, err := s.GetUploadWriter(ctx, component, bucket, path)
+	if err != nil {
+		return err
+	}
+
+	return upload(wc, r)
+}
+
+// Download copies the file of the object to w.
+func (s *Filesystem) Download(ctx context.Context, component string, bucket string, path string, w io.Writer) error {
+	rc, err := s.GetDownloadReader(ctx, component, bucket, path, false)
+	if err != nil {
+		return err
+	}
+
+	return download(rc, w)
+}
+
+// GetUploadWriter returns a writer to a temporary file, which replaces the
+// file of the object when the writer is closed.
+func (s *Filesystem) GetUploadWriter(ctx context.Context, component string, bucket string, path string) (io.WriteCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrap(errors.ErrObjectStorageUpload, err)
+	}
+
+	name, err := s.file(bucket, path)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrObjectStorageUpload, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return nil, errors.Wrap(errors.ErrObjectStorageUpload, err)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(name), tempPrefix+"*")
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrObjectStorageUpload, err)
+	}
+
+	// Temporary files are private, unlike the objects they become
+	_ = f.Chmod(0644)
+
+	return datacounter.NewObjectWriterCounter(ctx, component, bucket, &fileWriter{File: f, name: name}), nil
+}
+
+// GetDownloadReader returns the open file of the object, which implements
+// [io.ReaderAt] whatever seeker is.
+func (s *Filesystem) GetDownloadReader(ctx context.Context, component string, bucket string, path string, seeker bool) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrap(errors.ErrObjectStorageDownload, err)
+	}
+
+	name, err := s.file(bucket, path)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrObjectStorageDownload, err)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, wrapNotFound(errors.ErrObjectStorageDownload, err)
+	}
+
+	return datacounter.NewObjectReaderCounter(ctx, component, bucket, f), nil
+}
+
+// Stat returns the attributes of the file of the object.
+func (s *Filesystem) Stat(ctx context.Context, bucket string, path string) (ObjectInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return ObjectInfo{}, errors.Wrap(errors.ErrObjectStorageDownload, err)
+	}
+
+	name, err := s.file(bucket, path)
+	if err != nil {
+		return ObjectInfo{}, errors.Wrap(errors.ErrObjectStorageDownload, err)
+	}
+
+	fi, err := os.Stat(name)
+	if err != nil {
+		return ObjectInfo{}, wrapNotFound(errors.ErrObjectStorageDownload, err)
+	}
+
+	if !fi.Mode().IsRegular() {
+		return ObjectInfo{}, errors.Wrap(errors.ErrObjectNotFound, errors.ErrObjectStorageDownload, fs.ErrNotExist)
+	}
+
+	return ObjectInfo{Bucket: bucket, Path: path, Size: fi.Size(), Updated: fi.ModTime()}, nil
+}
+
+// Delete removes the file of the object.
+func (s *Filesystem) Delete(ctx context.Context, bucket string, path string) error {
+	if err := ctx.Err(); err != nil {
+		return errors.Wrap(errors.ErrObjectStorageDelete, err)
+	}
+
+	name, err := s.file(bucket, path)
+	if err != nil {
+		return errors.Wrap(errors.ErrObjectStorageDelete, err)
+	}
+
+	if err := os.Remove(name); err != nil {
+		return wrapNotFound(errors.ErrObjectStorageDelete, err)
+	}
+
+	return nil
+}
+
+// List walks the directory of the bucket for the files of the objects whose
+// path starts with prefix. A bucket without directory holds no object.
+func (s *Filesystem) List(ctx context.Context, bucket string, prefix string) ([]ObjectInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrap(errors.ErrObjectStorageDownload, err)
+	}
+
+	dir, err := s.file(bucket, "")
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrObjectStorageDownload, err)
+	}
+
+	var infos []ObjectInfo
+
+	err = filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && name == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+
+		if !d.Type().IsRegular() || strings.HasPrefix(d.Name(), tempPrefix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+
+		path := filepath.ToSlash(rel)
+		if !strings.HasPrefix(path, prefix) {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		infos = append(infos, ObjectInfo{Bucket: bucket, Path: path, Size: fi.Size(), Updated: fi.ModTime()})
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrObjectStorageDownload, err)
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Path < infos[j].Path })
+
+	return infos, nil
+}
+
+// file returns the name of the file of the object at path within bucket, or
+// of the directory of the bucket if path is empty. Buckets and paths that
+// would escape the root directory are rejected with [errors.ErrInvalidPath].
+func (s *Filesystem) file(bucket string, path string) (string, error) {
+	if bucket == "" || bucket == "." || bucket == ".." || strings.ContainsAny(bucket, `/\`) {
+		return "", errors.ErrInvalidPath
+	}
+
+	if path == "" {
+		return filepath.Join(s.root, bucket), nil
+	}
+
+	if !fs.ValidPath(path) || strings.Contains(path, `\`) {
+		return "", errors.ErrInvalidPath
+	}
+
+	return filepath.Join(s.root, bucket, filepath.FromSlash(path)), nil
+}
+
+// fileWriter writes an object to a temporary file, renamed to the file of the
+// object when closed.
+type fileWriter struct {
+	*os.File
+	name string
+}
+
+// Close closes the temporary file and renames it to the file of the object,
+// removing it if either fails.
+func (w *fileWriter) Close() error {
+	if err := w.File.Close(); err != nil {
+		_ = os.Remove(w.File.Name())
+		return errors.Wrap(errors.ErrObjectStorageUpload, err)
+	}
+
+	if err := os.Rename(w.File.Name(), w.name); err != nil {
+		_ = os.Remove(w.File.Name())
+		return errors.Wrap(errors.ErrObjectStorageUpload, err)
+	}
+
+	return nil
+}
+
+// Abort closes and removes the temporary file, leaving the file of the object
+// untouched.
+func (w *fileWriter) Abort() error {
+	_ = w.File.Close()
+
+	if err := os.Remove(w.File.Name()); err != nil {
+		return errors.Wrap(errors.ErrObjectStorageUpload, err)
+	}
+
+	return nil
+}
+
+// wrapNotFound wraps err with the given sentinel, preceded by
+// [errors.ErrObjectNotFound] if it reports a missing file.
+func wrapNotFound(sentinel error, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return errors.Wrap(errors.ErrObjectNotFound, sentinel, err)
+	}
+
+	return errors.Wrap(sentinel, err)
+}
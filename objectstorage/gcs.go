@@ -0,0 +1,70 @@
+package objectstorage
+
+import (
+	"context"
+	"io"
+
+	"github.com/stellarentropy/gravity-assist-common/gcp"
+)
+
+// GCS is the [ObjectStorage] backend storing objects in Google Cloud Storage,
+// through the functions of the gcp package.
+type GCS struct{}
+
+// NewGCS creates the Google Cloud Storage backend. The connection is only
+// established on first use.
+func NewGCS() *GCS {
+	return &GCS{}
+}
+
+// Upload copies r to the object, see [gcp.Upload].
+func (*GCS) Upload(ctx context.Context, component string, bucket string, path string, r io.Reader) error {
+	return gcp.Upload(ctx, component, bucket, path, r)
+}
+
+// Download copies the object to w, see [gcp.Download].
+func (*GCS) Download(ctx context.Context, component string, bucket string, path string, w io.Writer) error {
+	return gcp.Download(ctx, component, bucket, path, w)
+}
+
+// GetUploadWriter returns a writer to the object, see [gcp.GetUploadWriter].
+func (*GCS) GetUploadWriter(ctx context.Context, component string, bucket string, path string) (io.WriteCloser, error) {
+	return gcp.GetUploadWriter(ctx, component, bucket, path)
+}
+
+// GetDownloadReader returns a reader of the object, see
+// [gcp.GetDownloadReader].
+func (*GCS) GetDownloadReader(ctx context.Context, component string, bucket string, path string, seeker bool) (io.ReadCloser, error) {
+	return gcp.GetDownloadReader(ctx, component, bucket, path, seeker)
+}
+
+// Stat returns the attributes of the object, see [gcp.Stat].
+func (*GCS) Stat(ctx context.Context, bucket string, path string) (ObjectInfo, error) {
+	attrs, err := gcp.Stat(ctx, bucket, path)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	return ObjectInfo{Bucket: attrs.Bucket, Path: attrs.Name, Size: attrs.Size, Updated: attrs.Updated}, nil
+}
+
+// Delete removes the object, see [gcp.Delete].
+func (*GCS) Delete(ctx context.Context, bucket string, path string) error {
+	return gcp.Delete(ctx, bucket, path)
+}
+
+// List returns the attributes of the objects whose path starts with prefix,
+// see [gcp.List].
+func (*GCS) List(ctx context.Context, bucket string, prefix string) ([]ObjectInfo, error) {
+	objects, err := gcp.List(ctx, bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]ObjectInfo, 0, len(objects))
+	for _, attrs := range objects {
+		infos = append(infos, ObjectInfo{Bucket: attrs.Bucket, Path: attrs.Name, Size: attrs.Size, Updated: attrs.Updated})
+	}
+
+	return infos, nil
+}
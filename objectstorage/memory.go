@@ -0,0 +1,197 @@
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stellarentropy/gravity-assist-common/metrics/datacounter"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+)
+
+// Memory is the [ObjectStorage] backend keeping objects in memory, meant for
+// unit tests. Its objects are lost when it is garbage collected.
+type Memory struct {
+	mu      sync.RWMutex
+	buckets map[string]map[string]memoryObject
+}
+
+// memoryObject is an object kept by a [Memory] backend.
+type memoryObject struct {
+	data    []byte
+	updated time.Time
+}
+
+// NewMemory creates an empty in-memory backend.
+func NewMemory() *Memory {
+	return &Memory{buckets: map[string]map[string]memoryObject{}}
+}
+
+// Upload copies r to the object.
+func (s *Memory) Upload(ctx context.Context, component string, bucket string, path string, r io.Reader) error {
+	wc, err := s.GetUploadWriter(ctx, component, bucket, path)
+	if err != nil {
+		return err
+	}
+
+	return upload(wc, r)
+}
+
+// Download copies the object to w.
+func (s *Memory) Download(ctx context.Context, component string, bucket string, path string, w io.Writer) error {
+	rc, err := s.GetDownloadReader(ctx, component, bucket, path, false)
+	if err != nil {
+		return err
+	}
+
+	return download(rc, w)
+}
+
+// GetUploadWriter returns a writer buffering the object, which is stored when
+// the writer is closed.
+func (s *Memory) GetUploadWriter(ctx context.Context, component string, bucket string, path string) (io.WriteCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrap(errors.ErrObjectStorageUpload, err)
+	}
+
+	if bucket == "" || path == "" {
+		return nil, errors.Wrap(errors.ErrObjectStorageUpload, errors.ErrInvalidPath)
+	}
+
+	return datacounter.NewObjectWriterCounter(ctx, component, bucket, &memoryWriter{storage: s, bucket: bucket, path: path}), nil
+}
+
+// GetDownloadReader returns a reader of a snapshot of the object, which
+// implements [io.ReaderAt] whatever seeker is.
+func (s *Memory) GetDownloadReader(ctx context.Context, component string, bucket string, path string, seeker bool) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrap(errors.ErrObjectStorageDownload, err)
+	}
+
+	obj, ok := s.object(bucket, path)
+	if !ok {
+		return nil, errors.Wrap(errors.ErrObjectNotFound, errors.ErrObjectStorageDownload)
+	}
+
+	return datacounter.NewObjectReaderCounter(ctx, component, bucket, memoryReader{bytes.NewReader(obj.data)}), nil
+}
+
+// Stat returns the attributes of the object.
+func (s *Memory) Stat(ctx context.Context, bucket string, path string) (ObjectInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return ObjectInfo{}, errors.Wrap(errors.ErrObjectStorageDownload, err)
+	}
+
+	obj, ok := s.object(bucket, path)
+	if !ok {
+		return ObjectInfo{}, errors.Wrap(errors.ErrObjectNotFound, errors.ErrObjectStorageDownload)
+	}
+
+	return ObjectInfo{Bucket: bucket, Path: path, Size: int64(len(obj.data)), Updated: obj.updated}, nil
+}
+
+// Delete removes the object.
+func (s *Memory) Delete(ctx context.Context, bucket string, path string) error {
+	if err := ctx.Err(); err != nil {
+		return errors.Wrap(errors.ErrObjectStorageDelete, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.buckets[bucket][path]; !ok {
+		return errors.Wrap(errors.ErrObjectNotFound, errors.ErrObjectStorageDelete)
+	}
+
+	delete(s.buckets[bucket], path)
+
+	return nil
+}
+
+// List returns the attributes of the objects whose path starts with prefix.
+func (s *Memory) List(ctx context.Context, bucket string, prefix string) ([]ObjectInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrap(errors.ErrObjectStorageDownload, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var infos []ObjectInfo
+
+	for path, obj := range s.buckets[bucket] {
+		if strings.HasPrefix(path, prefix) {
+			infos = append(infos, ObjectInfo{Bucket: bucket, Path: path, Size: int64(len(obj.data)), Updated: obj.updated})
+		}
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Path < infos[j].Path })
+
+	return infos, nil
+}
+
+// object returns the object at path within bucket, and whether it exists.
+func (s *Memory) object(bucket string, path string) (memoryObject, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	obj, ok := s.buckets[bucket][path]
+
+	return obj, ok
+}
+
+// store records the object at path within bucket, replacing any previous one.
+func (s *Memory) store(bucket string, path string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.buckets[bucket] == nil {
+		s.buckets[bucket] = map[string]memoryObject{}
+	}
+
+	s.buckets[bucket][path] = memoryObject{data: data, updated: time.Now()}
+}
+
+// memoryWriter buffers an object, stored in a [Memory] backend when closed.
+type memoryWriter struct {
+	bytes.Buffer
+	storage *Memory
+	bucket  string
+	path    string
+	aborted bool
+}
+
+// Close stores the buffered object, unless it was aborted.
+func (w *memoryWriter) Close() error {
+	if w.aborted {
+		return errors.Wrap(errors.ErrObjectStorageUpload, os.ErrClosed)
+	}
+
+	w.storage.store(w.bucket, w.path, bytes.Clone(w.Bytes()))
+
+	return nil
+}
+
+// Abort discards the buffered object.
+func (w *memoryWriter) Abort() error {
+	w.Reset()
+	w.aborted = true
+
+	return nil
+}
+
+// memoryReader reads a snapshot of an object of a [Memory] backend.
+type memoryReader struct {
+	*bytes.Reader
+}
+
+// Close does nothing, as the snapshot needs no release.
+func (memoryReader) Close() error {
+	return nil
+}
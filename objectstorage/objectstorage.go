@@ -0,0 +1,118 @@
+package objectstorage
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/stellarentropy/gravity-assist-common/consts"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+)
+
+// ObjectStorage stores objects, addressed by a bucket and a path within it,
+// in a backend such as Google Cloud Storage, S3, the local filesystem or
+// memory.
+// Every backend records the bytes transferred with the object_storage
+// metrics of the datacounter package, attributed to the component given to
+// the transfer methods. Failures wrap [errors.ErrObjectStorageUpload],
+// [errors.ErrObjectStorageDownload] or [errors.ErrObjectStorageDelete], along
+// with [errors.ErrObjectNotFound] when the object does not exist.
+type ObjectStorage interface {
+	// Upload copies the content of r to the object at path within bucket,
+	// replacing it if it exists.
+	Upload(ctx context.Context, component string, bucket string, path string, r io.Reader) error
+
+	// Download copies the content of the object at path within bucket to w.
+	Download(ctx context.Context, component string, bucket string, path string, w io.Writer) error
+
+	// GetUploadWriter returns a writer to the object at path within bucket,
+	// which is only stored once the writer is closed successfully. The
+	// writers of the filesystem, in-memory and S3 backends also have an
+	// Abort method, which discards the object instead.
+	GetUploadWriter(ctx context.Context, component string, bucket string, path string) (io.WriteCloser, error)
+
+	// GetDownloadReader returns a reader of the object at path within bucket.
+	// When seeker is true, the reader also implements [io.ReaderAt].
+	GetDownloadReader(ctx context.Context, component string, bucket string, path string, seeker bool) (io.ReadCloser, error)
+
+	// Stat returns the attributes of the object at path within bucket.
+	Stat(ctx context.Context, bucket string, path string) (ObjectInfo, error)
+
+	// Delete removes the object at path within bucket.
+	Delete(ctx context.Context, bucket string, path string) error
+
+	// List returns the attributes of every object of bucket whose path starts
+	// with prefix, in lexicographic order of their paths.
+	List(ctx context.Context, bucket string, prefix string) ([]ObjectInfo, error)
+}
+
+// ObjectInfo describes an object stored in an [ObjectStorage].
+type ObjectInfo struct {
+	Bucket  string
+	Path    string
+	Size    int64
+	Updated time.Time
+}
+
+// New creates the [ObjectStorage] backend selected by c.
+func New(c *Config) (ObjectStorage, error) {
+	switch c.Backend {
+	case BackendGCS:
+		return NewGCS(), nil
+	case BackendFilesystem:
+		return NewFilesystem(c.Directory)
+	case BackendMemory:
+		return NewMemory(), nil
+	case BackendS3:
+		return NewS3(c)
+	}
+
+	return nil, errors.NewError(errors.ErrInvalidEnv).
+		WithSource(consts.SE).
+		WithField("env", BackendKey).
+		WithField("value", c.Backend)
+}
+
+// aborter is implemented by the writers of objects that can be discarded
+// instead of committed, such as those of the filesystem, in-memory and S3
+// backends.
+type aborter interface {
+	// Abort discards what was written and releases the writer, without
+	// storing the object.
+	Abort() error
+}
+
+// upload copies r to the writer of an object, committing it on close, as
+// [ObjectStorage.Upload] does for backends built on their
+// [ObjectStorage.GetUploadWriter]. If the copy fails, the object is aborted
+// so that no partial content is stored.
+func upload(wc io.WriteCloser, r io.Reader) error {
+	if _, err := io.Copy(wc, r); err != nil {
+		if a, ok := wc.(aborter); ok {
+			_ = a.Abort()
+		} else {
+			_ = wc.Close()
+		}
+		return errors.Wrap(errors.ErrObjectStorageUpload, err)
+	}
+
+	if err := wc.Close(); err != nil {
+		return errors.Wrap(errors.ErrObjectStorageUpload, err)
+	}
+
+	return nil
+}
+
+// download copies the reader of an object to w and closes it, as
+// [ObjectStorage.Download] does for backends built on their
+// [ObjectStorage.GetDownloadReader].
+func download(rc io.ReadCloser, w io.Writer) error {
+	defer func() { _ = rc.Close() }()
+
+	if _, err := io.Copy(w, rc); err != nil {
+		return errors.Wrap(errors.ErrObjectStorageDownload, err)
+	}
+
+	return nil
+}
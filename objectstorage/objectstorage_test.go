@@ -0,0 +1,206 @@
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stellarentropy/gravity-assist-common/config"
+	"github.com/stellarentropy/gravity-assist-common/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// testObjectStorage verifies the behaviour shared by every [ObjectStorage]
+// backend: transfers, random-access reads, listing, deletion and the errors
+// reported for missing objects.
+func testObjectStorage(t *testing.T, s ObjectStorage) {
+	ctx := context.Background()
+
+	assert.NoError(t, s.Upload(ctx, "test", "reports", "2024/01/report.csv", strings.NewReader("a,b\n1,2\n")))
+	assert.NoError(t, s.Upload(ctx, "test", "reports", "2024/02/report.csv", strings.NewReader("a,b\n")))
+	assert.NoError(t, s.Upload(ctx, "test", "reports", "2024.txt", strings.NewReader("index")))
+	assert.NoError(t, s.Upload(ctx, "test", "archive", "2024/01/report.csv", strings.NewReader("old")))
+
+	var buf bytes.Buffer
+	assert.NoError(t, s.Download(ctx, "test", "reports", "2024/01/report.csv", &buf))
+	assert.Equal(t, "a,b\n1,2\n", buf.String())
+
+	wc, err := s.GetUploadWriter(ctx, "test", "reports", "2024/01/report.csv")
+	assert.NoError(t, err)
+	_, err = io.WriteString(wc, "a,b\n3,4\n")
+	assert.NoError(t, err)
+	assert.NoError(t, wc.Close())
+
+	rc, err := s.GetDownloadReader(ctx, "test", "reports", "2024/01/report.csv", true)
+	assert.NoError(t, err)
+	part := make([]byte, 3)
+	n, err := rc.(io.ReaderAt).ReadAt(part, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, "3,4", string(part[:n]))
+	assert.NoError(t, rc.Close())
+
+	info, err := s.Stat(ctx, "reports", "2024/01/report.csv")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8), info.Size)
+	assert.False(t, info.Updated.IsZero())
+
+	infos, err := s.List(ctx, "reports", "2024/")
+	assert.NoError(t, err)
+	var paths []string
+	for _, info := range infos {
+		paths = append(paths, info.Path)
+	}
+	assert.Equal(t, []string{"2024/01/report.csv", "2024/02/report.csv"}, paths)
+
+	infos, err = s.List(ctx, "unknown", "")
+	assert.NoError(t, err)
+	assert.Empty(t, infos)
+
+	assert.NoError(t, s.Delete(ctx, "reports", "2024/02/report.csv"))
+
+	_, err = s.Stat(ctx, "reports", "2024/02/report.csv")
+	assert.True(t, errors.Is(err, errors.ErrObjectNotFound))
+
+	err = s.Download(ctx, "test", "reports", "2024/02/report.csv", io.Discard)
+	assert.True(t, errors.Is(err, errors.ErrObjectNotFound))
+	assert.True(t, errors.Is(err, errors.ErrObjectStorageDownload))
+
+	err = s.Delete(ctx, "reports", "2024/02/report.csv")
+	assert.True(t, errors.Is(err, errors.ErrObjectNotFound))
+}
+
+// failingReader returns its data, then fails instead of reporting the end of
+// the stream.
+type failingReader struct {
+	data string
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+
+	return n, nil
+}
+
+// testInterruptedUpload verifies that an upload whose reader fails stores
+// nothing, neither replacing an existing object nor creating a new one.
+func testInterruptedUpload(t *testing.T, s ObjectStorage) {
+	ctx := context.Background()
+
+	assert.NoError(t, s.Upload(ctx, "test", "interrupted", "existing.txt", strings.NewReader("complete")))
+
+	err := s.Upload(ctx, "test", "interrupted", "existing.txt", &failingReader{data: "partial"})
+	assert.True(t, errors.Is(err, errors.ErrObjectStorageUpload))
+
+	var buf bytes.Buffer
+	assert.NoError(t, s.Download(ctx, "test", "interrupted", "existing.txt", &buf))
+	assert.Equal(t, "complete", buf.String())
+
+	err = s.Upload(ctx, "test", "interrupted", "new.txt", &failingReader{data: "partial"})
+	assert.True(t, errors.Is(err, errors.ErrObjectStorageUpload))
+
+	_, err = s.Stat(ctx, "interrupted", "new.txt")
+	assert.True(t, errors.Is(err, errors.ErrObjectNotFound))
+
+	infos, err := s.List(ctx, "interrupted", "")
+	assert.NoError(t, err)
+	assert.Len(t, infos, 1)
+}
+
+// testCanceledContext verifies that every method fails with a canceled
+// context, wrapping the sentinel of its operation.
+func testCanceledContext(t *testing.T, s ObjectStorage) {
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.NoError(t, s.Upload(ctx, "test", "canceled", "report.csv", strings.NewReader("a,b\n")))
+	cancel()
+
+	err := s.Upload(ctx, "test", "canceled", "report.csv", strings.NewReader("a,b\n"))
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.True(t, errors.Is(err, errors.ErrObjectStorageUpload))
+
+	err = s.Download(ctx, "test", "canceled", "report.csv", io.Discard)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.True(t, errors.Is(err, errors.ErrObjectStorageDownload))
+
+	_, err = s.Stat(ctx, "canceled", "report.csv")
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.True(t, errors.Is(err, errors.ErrObjectStorageDownload))
+
+	_, err = s.List(ctx, "canceled", "")
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.True(t, errors.Is(err, errors.ErrObjectStorageDownload))
+
+	err = s.Delete(ctx, "canceled", "report.csv")
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.True(t, errors.Is(err, errors.ErrObjectStorageDelete))
+
+	_, err = s.Stat(context.Background(), "canceled", "report.csv")
+	assert.NoError(t, err)
+}
+
+// TestFilesystem runs the shared [ObjectStorage] checks against the
+// filesystem backend, and verifies that paths cannot escape its root.
+func TestFilesystem(t *testing.T) {
+	s, err := NewFilesystem(t.TempDir())
+	assert.NoError(t, err)
+
+	testObjectStorage(t, s)
+	testInterruptedUpload(t, s)
+	testCanceledContext(t, s)
+
+	err = s.Upload(context.Background(), "test", "reports", "../../etc/passwd", strings.NewReader(""))
+	assert.True(t, errors.Is(err, errors.ErrInvalidPath))
+	assert.True(t, errors.Is(err, errors.ErrObjectStorageUpload))
+
+	_, err = s.Stat(context.Background(), "..", "passwd")
+	assert.True(t, errors.Is(err, errors.ErrInvalidPath))
+}
+
+// TestMemory runs the shared [ObjectStorage] checks against the in-memory
+// backend.
+func TestMemory(t *testing.T) {
+	s := NewMemory()
+
+	testObjectStorage(t, s)
+	testInterruptedUpload(t, s)
+	testCanceledContext(t, s)
+}
+
+// TestNew verifies that the backend is selected through the configuration,
+// with the test profile defaulting to the in-memory backend.
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, tc := range []struct {
+		values map[string]string
+		want   ObjectStorage
+	}{
+		{map[string]string{config.ProfileKey: "test"}, &Memory{}},
+		{map[string]string{BackendKey: BackendFilesystem, "SE_GA_OBJECT_STORAGE_DIRECTORY": dir}, &Filesystem{}},
+		{map[string]string{BackendKey: BackendS3, "SE_GA_OBJECT_STORAGE_S3_ACCESS_KEY_ID": "AKIDEXAMPLE", "SE_GA_OBJECT_STORAGE_S3_SECRET_ACCESS_KEY": "secret"}, &S3{}},
+		{map[string]string{}, &GCS{}},
+	} {
+		l := config.NewMapLoader(tc.values)
+		c := NewConfig(l)
+		assert.NoError(t, l.Err())
+
+		s, err := New(c)
+		assert.NoError(t, err)
+		assert.IsType(t, tc.want, s)
+	}
+
+	for _, values := range []map[string]string{
+		{BackendKey: BackendFilesystem},
+		{BackendKey: BackendS3},
+	} {
+		l := config.NewMapLoader(values)
+		NewConfig(l)
+		assert.Error(t, l.Err())
+	}
+}
@@ -0,0 +1,544 @@
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stellarentropy/gravity-assist-common/consts"
+	"github.com/stellarentropy/gravity-assist-common/metrics/datacounter"
+
+	"github.com/stellarentropy/gravity-assist-common/errors"
+)
+
+// MinS3PartSize is the smallest size accepted by S3 for the parts of a
+// multipart upload, but the last one.
+const MinS3PartSize = 5 * 1024 * 1024
+
+// S3 is the [ObjectStorage] backend storing objects in Amazon S3 or in a
+// compatible service such as MinIO, through its REST API signed with the AWS
+// Signature Version 4. Objects larger than the part size are sent through a
+// multipart upload, and random-access reads are served by range requests.
+type S3 struct {
+	endpoint  *url.URL
+	region    string
+	creds     credentials
+	pathStyle bool
+	partSize  int64
+	client    *http.Client
+	now       func() time.Time
+}
+
+// NewS3 creates the S3 backend configured by c. Without endpoint, it reaches
+// the AWS endpoint of the configured region.
+func NewS3(c *Config) (*S3, error) {
+	if c.S3PartSize < MinS3PartSize {
+		return nil, errors.NewError(errors.ErrInvalidEnv).
+			WithSource(consts.SE).
+			WithField("env", "SE_GA_OBJECT_STORAGE_S3_PART_SIZE").
+			WithField("value", c.S3PartSize.String())
+	}
+
+	endpoint := c.S3Endpoint
+	if endpoint == nil {
+		endpoint = &url.URL{Scheme: "https", Host: "s3." + c.S3Region + ".amazonaws.com"}
+	}
+
+	return &S3{
+		endpoint: endpoint,
+		region:   c.S3Region,
+		creds: credentials{
+			accessKeyID:     c.S3AccessKeyID,
+			secretAccessKey: c.S3SecretAccessKey,
+			sessionToken:    c.S3SessionToken,
+		},
+		pathStyle: c.S3PathStyle,
+		partSize:  int64(c.S3PartSize),
+		client:    &http.Client{},
+		now:       time.Now,
+	}, nil
+}
+
+// Upload copies r to the object.
+func (s *S3) Upload(ctx context.Context, component string, bucket string, path string, r io.Reader) error {
+	wc, err := s.GetUploadWriter(ctx, component, bucket, path)
+	if err != nil {
+		return err
+	}
+
+	return upload(wc, r)
+}
+
+// Download copies the object to w.
+func (s *S3) Download(ctx context.Context, component string, bucket string, path string, w io.Writer) error {
+	rc, err := s.GetDownloadReader(ctx, component, bucket, path, false)
+	if err != nil {
+		return err
+	}
+
+	return download(rc, w)
+}
+
+// GetUploadWriter returns a writer buffering the object. The object is sent in
+// a single request when the writer is closed, unless it outgrows the part
+// size, in which case it is sent part by part through a multipart upload
+// completed when the writer is closed and aborted if any part fails.
+func (s *S3) GetUploadWriter(ctx context.Context, component string, bucket string, path string) (io.WriteCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrap(errors.ErrObjectStorageUpload, err)
+	}
+
+	if bucket == "" || path == "" {
+		return nil, errors.Wrap(errors.ErrObjectStorageUpload, errors.ErrInvalidPath)
+	}
+
+	return datacounter.NewObjectWriterCounter(ctx, component, bucket, &s3Writer{ctx: ctx, storage: s, bucket: bucket, path: path}), nil
+}
+
+// GetDownloadReader returns a reader streaming the object, which implements
+// [io.ReaderAt] whatever seeker is by sending a range request per read.
+func (s *S3) GetDownloadReader(ctx context.Context, component string, bucket string, path string, seeker bool) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrap(errors.ErrObjectStorageDownload, err)
+	}
+
+	if bucket == "" || path == "" {
+		return nil, errors.Wrap(errors.ErrObjectStorageDownload, errors.ErrInvalidPath)
+	}
+
+	resp, err := s.do(ctx, http.MethodGet, bucket, path, nil, nil, nil)
+	if err != nil {
+		return nil, wrapS3Error(errors.ErrObjectStorageDownload, err)
+	}
+
+	r := &s3Reader{ctx: ctx, storage: s, bucket: bucket, path: path, body: resp.Body}
+
+	return datacounter.NewObjectReaderCounter(ctx, component, bucket, r), nil
+}
+
+// Stat returns the attributes of the object, read from the headers of a HEAD
+// request.
+func (s *S3) Stat(ctx context.Context, bucket string, path string) (ObjectInfo, error) {
+	if bucket == "" || path == "" {
+		return ObjectInfo{}, errors.Wrap(errors.ErrObjectStorageDownload, errors.ErrInvalidPath)
+	}
+
+	resp, err := s.do(ctx, http.MethodHead, bucket, path, nil, nil, nil)
+	if err != nil {
+		return ObjectInfo{}, wrapS3Error(errors.ErrObjectStorageDownload, err)
+	}
+	_ = resp.Body.Close()
+
+	updated, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+
+	return ObjectInfo{Bucket: bucket, Path: path, Size: resp.ContentLength, Updated: updated}, nil
+}
+
+// Delete removes the object. As S3 reports success when deleting a missing
+// object, its existence is checked first.
+func (s *S3) Delete(ctx context.Context, bucket string, path string) error {
+	if bucket == "" || path == "" {
+		return errors.Wrap(errors.ErrObjectStorageDelete, errors.ErrInvalidPath)
+	}
+
+	resp, err := s.do(ctx, http.MethodHead, bucket, path, nil, nil, nil)
+	if err != nil {
+		return wrapS3Error(errors.ErrObjectStorageDelete, err)
+	}
+	_ = resp.Body.Close()
+
+	resp, err = s.do(ctx, http.MethodDelete, bucket, path, nil, nil, nil)
+	if err != nil {
+		return wrapS3Error(errors.ErrObjectStorageDelete, err)
+	}
+	_ = resp.Body.Close()
+
+	return nil
+}
+
+// List returns the attributes of the objects whose path starts with prefix,
+// following the pages of the ListObjectsV2 results, which S3 sorts by path.
+// A bucket that does not exist holds no object.
+func (s *S3) List(ctx context.Context, bucket string, prefix string) ([]ObjectInfo, error) {
+	if bucket == "" {
+		return nil, errors.Wrap(errors.ErrObjectStorageDownload, errors.ErrInvalidPath)
+	}
+
+	var infos []ObjectInfo
+
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+	for {
+		resp, err := s.do(ctx, http.MethodGet, bucket, "", query, nil, nil)
+		if err != nil {
+			var s3Err *s3Error
+			if errors.As(err, &s3Err) && s3Err.Code == "NoSuchBucket" {
+				return nil, nil
+			}
+			return nil, wrapS3Error(errors.ErrObjectStorageDownload, err)
+		}
+
+		var result s3ListBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrObjectStorageDownload, err)
+		}
+
+		for _, obj := range result.Contents {
+			infos = append(infos, ObjectInfo{Bucket: bucket, Path: obj.Key, Size: obj.Size, Updated: obj.LastModified})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return infos, nil
+		}
+
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
+
+// do sends a signed request for the object at path within bucket, or for the
+// bucket itself if path is empty. Responses with an error status are closed
+// and returned as an [*s3Error].
+func (s *S3) do(ctx context.Context, method string, bucket string, path string, query url.Values, header http.Header, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.url(bucket, path, query).String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	signV4(req, s.creds, s.region, hashSHA256(body), s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		defer func() { _ = resp.Body.Close() }()
+		return nil, newS3Error(resp)
+	}
+
+	return resp, nil
+}
+
+// url returns the URL of the object at path within bucket, or of the bucket
+// itself if path is empty, with the bucket in the path or in the host name
+// depending on the addressing style.
+func (s *S3) url(bucket string, path string, query url.Values) *url.URL {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	if s.pathStyle {
+		u.Path += "/" + bucket
+	} else {
+		u.Host = bucket + "." + u.Host
+	}
+
+	if path != "" || !s.pathStyle {
+		u.Path += "/" + path
+	}
+
+	u.RawPath = uriEncode(u.Path, false)
+	u.RawQuery = canonicalQuery(query)
+
+	return &u
+}
+
+// s3Writer buffers an object written to an [S3] backend, sending it once
+// closed or part by part once it outgrows the part size.
+type s3Writer struct {
+	ctx      context.Context
+	storage  *S3
+	bucket   string
+	path     string
+	buf      bytes.Buffer
+	uploadID string
+	parts    []s3CompletedPart
+	err      error
+	closed   bool
+}
+
+// Write buffers p, sending every full part through the multipart upload.
+func (w *s3Writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.Wrap(errors.ErrObjectStorageUpload, os.ErrClosed)
+	}
+
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	w.buf.Write(p)
+
+	// p is buffered as a whole, and so reported as written even if sending a
+	// part fails
+	for int64(w.buf.Len()) >= w.storage.partSize {
+		if err := w.uploadPart(w.buf.Next(int(w.storage.partSize))); err != nil {
+			return len(p), w.fail(err)
+		}
+	}
+
+	return len(p), nil
+}
+
+// Close sends the buffered object in a single request, or its last part
+// before completing the multipart upload. Later calls fail with
+// [os.ErrClosed].
+func (w *s3Writer) Close() error {
+	if w.closed {
+		return errors.Wrap(errors.ErrObjectStorageUpload, os.ErrClosed)
+	}
+
+	w.closed = true
+
+	if w.err != nil {
+		return w.err
+	}
+
+	if w.uploadID == "" {
+		resp, err := w.storage.do(w.ctx, http.MethodPut, w.bucket, w.path, nil, nil, w.buf.Bytes())
+		if err != nil {
+			return w.fail(err)
+		}
+		_ = resp.Body.Close()
+
+		return nil
+	}
+
+	if w.buf.Len() > 0 {
+		if err := w.uploadPart(w.buf.Bytes()); err != nil {
+			return w.fail(err)
+		}
+	}
+
+	body, err := xml.Marshal(s3CompleteMultipartUpload{Parts: w.parts})
+	if err != nil {
+		return w.fail(err)
+	}
+
+	query := url.Values{"uploadId": {w.uploadID}}
+	resp, err := w.storage.do(w.ctx, http.MethodPost, w.bucket, w.path, query, nil, body)
+	if err != nil {
+		return w.fail(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// The completion may fail after the response status has been sent, in
+	// which case the error is only reported in the body
+	var result struct {
+		XMLName xml.Name
+		s3Error
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return w.fail(err)
+	}
+
+	if result.XMLName.Local == "Error" {
+		result.Status = resp.StatusCode
+		return w.fail(&result.s3Error)
+	}
+
+	return nil
+}
+
+// Abort discards the buffered object without sending it, and aborts the
+// multipart upload if one was initiated, so that neither the object nor its
+// parts are stored. Aborting a closed writer does nothing.
+func (w *s3Writer) Abort() error {
+	if w.closed {
+		return nil
+	}
+
+	w.buf.Reset()
+
+	if w.err == nil {
+		_ = w.fail(os.ErrClosed)
+	}
+
+	return nil
+}
+
+// uploadPart sends data as the next part of the multipart upload, which is
+// initiated on the first part.
+func (w *s3Writer) uploadPart(data []byte) error {
+	if w.uploadID == "" {
+		resp, err := w.storage.do(w.ctx, http.MethodPost, w.bucket, w.path, url.Values{"uploads": {""}}, nil, nil)
+		if err != nil {
+			return err
+		}
+
+		var result s3InitiateMultipartUploadResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		_ = resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		w.uploadID = result.UploadID
+	}
+
+	number := len(w.parts) + 1
+	query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {w.uploadID}}
+
+	resp, err := w.storage.do(w.ctx, http.MethodPut, w.bucket, w.path, query, nil, data)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	w.parts = append(w.parts, s3CompletedPart{PartNumber: number, ETag: resp.Header.Get("ETag")})
+
+	return nil
+}
+
+// fail records err as the outcome of the upload, aborting the multipart
+// upload if one was initiated so that its parts are not left behind.
+func (w *s3Writer) fail(err error) error {
+	w.err = wrapS3Error(errors.ErrObjectStorageUpload, err)
+
+	if w.uploadID != "" {
+		// The upload already failed, a failure to abort it is not reported
+		query := url.Values{"uploadId": {w.uploadID}}
+		if resp, err := w.storage.do(context.WithoutCancel(w.ctx), http.MethodDelete, w.bucket, w.path, query, nil, nil); err == nil {
+			_ = resp.Body.Close()
+		}
+		w.uploadID = ""
+	}
+
+	return w.err
+}
+
+// s3Reader streams an object of an [S3] backend, and reads ranges of it
+// through separate requests.
+type s3Reader struct {
+	ctx     context.Context
+	storage *S3
+	bucket  string
+	path    string
+	body    io.ReadCloser
+}
+
+// Read reads the streamed object.
+func (r *s3Reader) Read(p []byte) (int, error) {
+	return r.body.Read(p)
+}
+
+// ReadAt reads len(p) bytes of the object starting at off through a range
+// request, returning [io.EOF] if the object ends before. Responses other than
+// partial content are rejected, as their body does not start at off.
+func (r *s3Reader) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	header := http.Header{"Range": {fmt.Sprintf("bytes=%d-%d", off, off+int64(len(p))-1)}}
+
+	resp, err := r.storage.do(r.ctx, http.MethodGet, r.bucket, r.path, nil, header, nil)
+	if err != nil {
+		var s3Err *s3Error
+		if errors.As(err, &s3Err) && s3Err.Status == http.StatusRequestedRangeNotSatisfiable {
+			return 0, io.EOF
+		}
+		return 0, wrapS3Error(errors.ErrObjectStorageDownload, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// A server ignoring the range answers with the whole object instead
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, errors.Wrap(errors.ErrObjectStorageDownload,
+			errors.New("range request answered with status %d", resp.StatusCode))
+	}
+
+	n, err := io.ReadFull(resp.Body, p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return n, io.EOF
+	}
+	if err != nil {
+		return n, errors.Wrap(errors.ErrObjectStorageDownload, err)
+	}
+
+	return n, nil
+}
+
+// Close releases the streamed object.
+func (r *s3Reader) Close() error {
+	return r.body.Close()
+}
+
+// s3Error is the error reported by S3 in the body of a failed response, along
+// with the status of the response.
+type s3Error struct {
+	Status  int    `xml:"-"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// newS3Error reads the error reported by the failed response resp. Responses
+// without body, such as those of HEAD requests, only carry their status.
+func newS3Error(resp *http.Response) *s3Error {
+	e := &s3Error{}
+	_ = xml.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(e)
+	e.Status = resp.StatusCode
+
+	return e
+}
+
+// Error returns the status, code and message of the error.
+func (e *s3Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("s3: %d %s", e.Status, http.StatusText(e.Status))
+	}
+
+	return fmt.Sprintf("s3: %d %s: %s", e.Status, e.Code, e.Message)
+}
+
+// wrapS3Error wraps err with the given sentinel, preceded by
+// [errors.ErrObjectNotFound] if S3 reported a missing object.
+func wrapS3Error(sentinel error, err error) error {
+	var s3Err *s3Error
+	if errors.As(err, &s3Err) && s3Err.Status == http.StatusNotFound && (s3Err.Code == "" || s3Err.Code == "NoSuchKey") {
+		return errors.Wrap(errors.ErrObjectNotFound, sentinel, err)
+	}
+
+	return errors.Wrap(sentinel, err)
+}
+
+// s3InitiateMultipartUploadResult is the response to the initiation of a
+// multipart upload.
+type s3InitiateMultipartUploadResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+// s3CompleteMultipartUpload is the request completing a multipart upload.
+type s3CompleteMultipartUpload struct {
+	XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+	Parts   []s3CompletedPart `xml:"Part"`
+}
+
+// s3CompletedPart identifies a part of a multipart upload.
+type s3CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// s3ListBucketResult is a page of the response to a ListObjectsV2 request.
+type s3ListBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}